@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolHints describes how a tool behaves so hosts can decide whether to
+// ask the user before calling it. mcp.NewTool assumes the worst (destructive
+// and open-world), so every tool states all four hints through withHints.
+type toolHints struct {
+	// Title is the human-readable name hosts show instead of the tool name.
+	Title string
+	// ReadOnly tools do not modify anything.
+	ReadOnly bool
+	// Destructive tools may delete or overwrite data, or spend money. Only
+	// meaningful when ReadOnly is false; callers must confirm them first.
+	Destructive bool
+	// Idempotent tools have no additional effect when repeated with the
+	// same arguments.
+	Idempotent bool
+	// OpenWorld tools reach systems outside this server.
+	OpenWorld bool
+}
+
+// withHints sets the tool's annotations from h.
+func withHints(h toolHints) mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		tool.Annotations = mcp.ToolAnnotation{
+			Title:           h.Title,
+			ReadOnlyHint:    mcp.ToBoolPtr(h.ReadOnly),
+			DestructiveHint: mcp.ToBoolPtr(!h.ReadOnly && h.Destructive),
+			IdempotentHint:  mcp.ToBoolPtr(h.Idempotent),
+			OpenWorldHint:   mcp.ToBoolPtr(h.OpenWorld),
+		}
+	}
+}
+
+// isDestructive reports whether tool is annotated as destructive. Tools
+// without a read-only hint count as destructive, as the spec defaults do.
+func isDestructive(tool mcp.Tool) bool {
+	a := tool.Annotations
+	if a.ReadOnlyHint != nil && *a.ReadOnlyHint {
+		return false
+	}
+	return a.DestructiveHint == nil || *a.DestructiveHint
+}
+
+// confirmDestructive is tool handler middleware that asks the user to
+// confirm calls to destructive tools through elicitation. Clients without
+// elicitation support are expected to confirm based on the hints themselves,
+// so their calls go through unchanged.
+func confirmDestructive(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s := server.ServerFromContext(ctx)
+		if s == nil || !supportsElicitation(ctx) {
+			return next(ctx, request)
+		}
+		tool := s.GetTool(request.Params.Name)
+		if tool == nil || !isDestructive(tool.Tool) {
+			return next(ctx, request)
+		}
+
+		title := tool.Tool.Annotations.Title
+		if title == "" {
+			title = tool.Tool.Name
+		}
+		result, err := elicit(ctx, fmt.Sprintf("%s cannot be undone. Do you want to continue?", title), map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"confirm": map[string]any{
+					"type":        "boolean",
+					"title":       "Run " + title,
+					"description": fmt.Sprintf("Allow %s to run with the arguments shown", title),
+				},
+			},
+			"required": []string{"confirm"},
+		})
+		if err != nil {
+			log.Printf("Confirmation for %s failed: %v", request.Params.Name, err)
+			return mcp.NewToolResultError(fmt.Sprintf("%s was not run: confirmation failed (%v)", title, err)), nil
+		}
+		if !elicitAccepted(result) || !elicitBool(result, "confirm") {
+			return mcp.NewToolResultError(fmt.Sprintf("%s was not run: the user did not confirm it", title)), nil
+		}
+		return next(ctx, request)
+	}
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tool arguments are declared as structs. Each exported field is one
+// argument:
+//
+//	type generateAssetArgs struct {
+//		AssetType string `json:"asset_type" validate:"required,enum=banner|flyer" description:"Type of asset"`
+//	}
+//
+// The json tag names the argument, description documents it and validate
+// holds comma-separated rules:
+//
+//	required       the argument must be present (and non-empty for strings)
+//	min=N, max=N   bounds for numbers, string length or array length
+//	enum=a|b|c     allowed string values
+//	pattern=RE     regular expression a string must match (no commas)
+//
+// withArguments derives the tool's input schema from the struct and
+// typedToolHandler binds and validates the arguments before calling the
+// handler, so both always agree.
+
+// FieldError describes one invalid argument.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ArgumentError lists every invalid argument of a tool call.
+type ArgumentError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ArgumentError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Field + " " + fe.Message
+	}
+	return "invalid arguments: " + strings.Join(parts, "; ")
+}
+
+func (e *ArgumentError) add(field, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// msgRequired is the message of a missing required argument.
+const msgRequired = "is required"
+
+// missing returns the names of the missing arguments if that is the only
+// problem, or nil otherwise.
+func (e *ArgumentError) missing() []string {
+	var names []string
+	for _, fe := range e.Errors {
+		if fe.Message != msgRequired {
+			return nil
+		}
+		names = append(names, fe.Field)
+	}
+	return names
+}
+
+// Result renders the error as a tool error result whose structuredContent
+// carries the same list, so clients can highlight each field.
+func (e *ArgumentError) Result() *mcp.CallToolResult {
+	text := "Invalid arguments:"
+	for _, fe := range e.Errors {
+		text += fmt.Sprintf("\n- %s: %s", fe.Field, fe.Message)
+	}
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{mcp.NewTextContent(text)},
+		StructuredContent: e,
+		IsError:           true,
+	}
+}
+
+// argField is the parsed form of one struct field.
+type argField struct {
+	index       int
+	name        string
+	description string
+	required    bool
+	min, max    *float64
+	enum        []string
+	pattern     *regexp.Regexp
+}
+
+type argSpec struct {
+	fields []argField
+}
+
+var argSpecs sync.Map // reflect.Type -> *argSpec
+
+// argSpecFor parses the tags of struct type t once and caches the result.
+// Malformed tags are programming errors and panic at registration time.
+func argSpecFor(t reflect.Type) *argSpec {
+	if spec, ok := argSpecs.Load(t); ok {
+		return spec.(*argSpec)
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("tool arguments must be a struct, got %s", t))
+	}
+
+	spec := &argSpec{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		field := argField{index: i, name: name, description: sf.Tag.Get("description")}
+		for _, rule := range strings.Split(sf.Tag.Get("validate"), ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+			switch key {
+			case "":
+			case "required":
+				field.required = true
+			case "min", "max":
+				n, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					panic(fmt.Sprintf("%s.%s: invalid %s %q", t, sf.Name, key, value))
+				}
+				if key == "min" {
+					field.min = &n
+				} else {
+					field.max = &n
+				}
+			case "enum":
+				field.enum = strings.Split(value, "|")
+			case "pattern":
+				field.pattern = regexp.MustCompile(value)
+			default:
+				panic(fmt.Sprintf("%s.%s: unknown validate rule %q", t, sf.Name, key))
+			}
+		}
+		spec.fields = append(spec.fields, field)
+	}
+
+	actual, _ := argSpecs.LoadOrStore(t, spec)
+	return actual.(*argSpec)
+}
+
+// withArguments sets the tool's input schema from the argument struct T.
+func withArguments[T any]() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		tool.InputSchema = argumentsSchema(reflect.TypeOf((*T)(nil)).Elem())
+	}
+}
+
+func argumentsSchema(t reflect.Type) mcp.ToolInputSchema {
+	spec := argSpecFor(t)
+	schema := mcp.ToolInputSchema{
+		Type:       "object",
+		Properties: make(map[string]any, len(spec.fields)),
+	}
+	for _, f := range spec.fields {
+		prop := typeSchema(t.Field(f.index).Type)
+		if f.description != "" {
+			prop["description"] = f.description
+		}
+		if len(f.enum) > 0 {
+			prop["enum"] = f.enum
+		}
+		if f.pattern != nil {
+			prop["pattern"] = f.pattern.String()
+		}
+		minKey, maxKey := "minimum", "maximum"
+		switch prop["type"] {
+		case "string":
+			minKey, maxKey = "minLength", "maxLength"
+		case "array":
+			minKey, maxKey = "minItems", "maxItems"
+		}
+		if f.min != nil {
+			prop[minKey] = *f.min
+		}
+		if f.max != nil {
+			prop[maxKey] = *f.max
+		}
+		if f.required {
+			schema.Required = append(schema.Required, f.name)
+		}
+		schema.Properties[f.name] = prop
+	}
+	return schema
+}
+
+// typeSchema maps a Go type to its JSON Schema type.
+func typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Struct:
+		nested := argumentsSchema(t)
+		prop := map[string]any{"type": "object", "properties": nested.Properties}
+		if len(nested.Required) > 0 {
+			prop["required"] = nested.Required
+		}
+		return prop
+	default:
+		return map[string]any{"type": "object"}
+	}
+}
+
+// jsonTypeName returns the JSON type of t with its article, for "must be
+// ..." messages.
+func jsonTypeName(t reflect.Type) string {
+	name, _ := typeSchema(t)["type"].(string)
+	switch name {
+	case "":
+		return "a valid value"
+	case "array", "integer", "object":
+		return "an " + name
+	default:
+		return "a " + name
+	}
+}
+
+// bindArguments decodes and validates the arguments of request into a T.
+// The returned error, if any, is an *ArgumentError covering every field.
+func bindArguments[T any](request mcp.CallToolRequest) (T, error) {
+	return bindValues[T](request.Params.Arguments)
+}
+
+// bindValues is bindArguments for a raw arguments value.
+func bindValues[T any](arguments any) (T, error) {
+	var out T
+	v := reflect.ValueOf(&out).Elem()
+	spec := argSpecFor(v.Type())
+	argErr := &ArgumentError{}
+
+	var raw map[string]any
+	switch args := arguments.(type) {
+	case nil:
+	case map[string]any:
+		raw = args
+	default:
+		argErr.add("arguments", "must be an object")
+		return out, argErr
+	}
+
+	for _, f := range spec.fields {
+		value := raw[f.name]
+		if !checkPresent(argErr, f, value) {
+			continue
+		}
+
+		target := v.Field(f.index)
+		data, _ := json.Marshal(value)
+		if err := json.Unmarshal(data, target.Addr().Interface()); err != nil {
+			argErr.add(f.name, "must be %s", jsonTypeName(target.Type()))
+			continue
+		}
+		validateField(argErr, f, target)
+		validateNested(argErr, f.name, value, target)
+	}
+
+	if len(argErr.Errors) > 0 {
+		return out, argErr
+	}
+	return out, nil
+}
+
+// checkPresent reports whether value, the raw argument of f, is given,
+// recording an error if f is required and it is not. An empty string counts
+// as not given.
+func checkPresent(argErr *ArgumentError, f argField, value any) bool {
+	if s, ok := value.(string); value == nil || ok && s == "" {
+		if f.required {
+			argErr.add(f.name, msgRequired)
+		}
+		return false
+	}
+	return true
+}
+
+// validateNested applies the rules of the struct fields inside value, an
+// argument bound from raw, naming them by path such as "items[0].name".
+func validateNested(argErr *ArgumentError, path string, raw any, value reflect.Value) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		fields, _ := raw.(map[string]any)
+		for _, f := range argSpecFor(value.Type()).fields {
+			fieldRaw := fields[f.name]
+			f.name = path + "." + f.name
+			if !checkPresent(argErr, f, fieldRaw) {
+				continue
+			}
+			validateField(argErr, f, value.Field(f.index))
+			validateNested(argErr, f.name, fieldRaw, value.Field(f.index))
+		}
+	case reflect.Slice, reflect.Array:
+		items, _ := raw.([]any)
+		for i := 0; i < value.Len() && i < len(items); i++ {
+			validateNested(argErr, fmt.Sprintf("%s[%d]", path, i), items[i], value.Index(i))
+		}
+	}
+}
+
+// validateField applies the min/max, enum and pattern rules of f.
+func validateField(argErr *ArgumentError, f argField, value reflect.Value) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	var size float64
+	var unit string
+	switch value.Kind() {
+	case reflect.String:
+		size, unit = float64(len([]rune(value.String()))), " characters"
+	case reflect.Slice, reflect.Array:
+		size, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = value.Float()
+	default:
+		return
+	}
+	if f.min != nil && size < *f.min {
+		argErr.add(f.name, "must be at least %s%s", strconv.FormatFloat(*f.min, 'f', -1, 64), unit)
+	}
+	if f.max != nil && size > *f.max {
+		argErr.add(f.name, "must be at most %s%s", strconv.FormatFloat(*f.max, 'f', -1, 64), unit)
+	}
+
+	if value.Kind() != reflect.String {
+		return
+	}
+	s := value.String()
+	if len(f.enum) > 0 {
+		found := false
+		for _, allowed := range f.enum {
+			if s == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			argErr.add(f.name, "must be one of %s", strings.Join(f.enum, ", "))
+		}
+	}
+	if f.pattern != nil && !f.pattern.MatchString(s) {
+		argErr.add(f.name, "must match %s", f.pattern)
+	}
+}
+
+// typedToolHandler adapts a handler that takes bound arguments to a
+// server.ToolHandlerFunc. Invalid arguments never reach the handler; the
+// caller gets an error result listing every problem instead. When the only
+// problem is missing arguments and the client supports elicitation, the user
+// is asked for them first.
+func typedToolHandler[T any](handler func(ctx context.Context, request mcp.CallToolRequest, args T) (*mcp.CallToolResult, error)) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := bindArguments[T](request)
+		if argErr, ok := err.(*ArgumentError); ok && argErr.missing() != nil && supportsElicitation(ctx) {
+			var result *mcp.CallToolResult
+			args, result, err = elicitMissingArguments[T](ctx, request, argErr)
+			if result != nil {
+				return result, nil
+			}
+		}
+		if err != nil {
+			if argErr, ok := err.(*ArgumentError); ok {
+				return argErr.Result(), nil
+			}
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return handler(ctx, request, args)
+	}
+}
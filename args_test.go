@@ -0,0 +1,130 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testItem struct {
+	Name  string `json:"name" validate:"required,max=5"`
+	Count int    `json:"count" validate:"min=1"`
+}
+
+type testArgs struct {
+	Query string     `json:"query" validate:"required,min=2"`
+	Sort  string     `json:"sort" validate:"enum=asc|desc"`
+	Code  string     `json:"code" validate:"pattern=^[A-Z]+$"`
+	Limit *int       `json:"limit" validate:"min=1,max=10"`
+	Tags  []string   `json:"tags" validate:"max=2"`
+	Items []testItem `json:"items"`
+	Owner *testItem  `json:"owner"`
+}
+
+func TestBindValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments any
+		errors    []FieldError
+	}{
+		{
+			name:      "valid",
+			arguments: map[string]any{"query": "shoes", "sort": "asc", "code": "AB", "limit": 5, "tags": []any{"a"}},
+		},
+		{
+			name:      "missing required",
+			arguments: nil,
+			errors:    []FieldError{{"query", "is required"}},
+		},
+		{
+			name:      "empty string counts as missing",
+			arguments: map[string]any{"query": ""},
+			errors:    []FieldError{{"query", "is required"}},
+		},
+		{
+			name:      "empty optional string skips its rules",
+			arguments: map[string]any{"query": "shoes", "sort": "", "code": ""},
+		},
+		{
+			name:      "not an object",
+			arguments: []any{"shoes"},
+			errors:    []FieldError{{"arguments", "must be an object"}},
+		},
+		{
+			name:      "wrong types",
+			arguments: map[string]any{"query": 3, "limit": "five", "tags": "a", "owner": "me"},
+			errors: []FieldError{
+				{"query", "must be a string"},
+				{"limit", "must be an integer"},
+				{"tags", "must be an array"},
+				{"owner", "must be an object"},
+			},
+		},
+		{
+			name:      "rules",
+			arguments: map[string]any{"query": "s", "sort": "up", "code": "ab", "limit": 11, "tags": []any{"a", "b", "c"}},
+			errors: []FieldError{
+				{"query", "must be at least 2 characters"},
+				{"sort", "must be one of asc, desc"},
+				{"code", "must match ^[A-Z]+$"},
+				{"limit", "must be at most 10"},
+				{"tags", "must be at most 2 items"},
+			},
+		},
+		{
+			name: "nested structs",
+			arguments: map[string]any{
+				"query": "shoes",
+				"items": []any{map[string]any{"name": "ok", "count": 1}, map[string]any{"count": 0}},
+				"owner": map[string]any{"name": "toolong", "count": 1},
+			},
+			errors: []FieldError{
+				{"items[1].name", "is required"},
+				{"items[1].count", "must be at least 1"},
+				{"owner.name", "must be at most 5 characters"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bindValues[testArgs](tt.arguments)
+			var errors []FieldError
+			if err != nil {
+				argErr, ok := err.(*ArgumentError)
+				if !ok {
+					t.Fatalf("err = %T, want *ArgumentError", err)
+				}
+				errors = argErr.Errors
+			}
+			if !reflect.DeepEqual(errors, tt.errors) {
+				t.Errorf("errors = %v, want %v", errors, tt.errors)
+			}
+		})
+	}
+}
+
+func TestBindValuesDecodes(t *testing.T) {
+	args, err := bindValues[testArgs](map[string]any{"query": "shoes", "limit": 3.0, "items": []any{map[string]any{"name": "a", "count": 2}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.Query != "shoes" || args.Limit == nil || *args.Limit != 3 || len(args.Items) != 1 || args.Items[0].Count != 2 {
+		t.Errorf("args = %+v", args)
+	}
+}
+
+func TestArgumentErrorMissing(t *testing.T) {
+	tests := []struct {
+		errors []FieldError
+		want   []string
+	}{
+		{[]FieldError{{"a", msgRequired}, {"b", msgRequired}}, []string{"a", "b"}},
+		{[]FieldError{{"a", msgRequired}, {"b", "must be a string"}}, nil},
+	}
+	for _, tt := range tests {
+		e := &ArgumentError{Errors: tt.errors}
+		if got := e.missing(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("missing() = %v, want %v", got, tt.want)
+		}
+	}
+}
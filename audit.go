@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AuditConfig controls the JSONL audit log of MCP requests.
+type AuditConfig struct {
+	Enabled bool   `json:"enabled"`
+	Path    string `json:"path"`
+	// MaxSizeMB is the size at which the log is rotated to Path.1, Path.2, ...
+	MaxSizeMB  int `json:"max_size_mb"`
+	MaxBackups int `json:"max_backups"`
+	// RedactKeys lists argument names (case-insensitive, at any depth) whose
+	// values are replaced before the entry is written.
+	RedactKeys []string `json:"redact_keys"`
+	// OmitArguments drops tool arguments from the log entirely.
+	OmitArguments bool `json:"omit_arguments"`
+	// RecordResponses stores each JSON-RPC result so the capture can be
+	// replayed and diffed with the replay command.
+	RecordResponses bool `json:"record_responses"`
+}
+
+const redactedValue = "[REDACTED]"
+
+// auditEntry is one line of the audit log. The HTTP middleware creates it and
+// the MCP hooks fill in what they learn while the request is dispatched.
+type auditEntry struct {
+	Time       time.Time `json:"time"`
+	SessionID  string    `json:"session_id,omitempty"`
+	RequestID  any       `json:"request_id,omitempty"`
+	Method     string    `json:"method"`
+	Tool       string    `json:"tool,omitempty"`
+	Resource   string    `json:"resource,omitempty"`
+	Prompt     string    `json:"prompt,omitempty"`
+	Arguments  any       `json:"arguments,omitempty"`
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+	Response   any       `json:"response,omitempty"`
+	LatencyMs  float64   `json:"latency_ms"`
+	HTTPStatus int       `json:"http_status,omitempty"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+
+	mu sync.Mutex
+}
+
+const (
+	outcomeSuccess   = "success"
+	outcomeToolError = "tool_error"
+	outcomeError     = "error"
+)
+
+type auditEntryKey struct{}
+
+func auditEntryFromContext(ctx context.Context) *auditEntry {
+	entry, _ := ctx.Value(auditEntryKey{}).(*auditEntry)
+	return entry
+}
+
+// AuditLogger appends audit entries as JSONL and rotates the file by size.
+type AuditLogger struct {
+	cfg    AuditConfig
+	redact map[string]bool
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewAuditLogger opens (or creates) the audit log described by cfg.
+func NewAuditLogger(cfg AuditConfig) (*AuditLogger, error) {
+	redact := make(map[string]bool, len(cfg.RedactKeys))
+	for _, key := range cfg.RedactKeys {
+		redact[strings.ToLower(key)] = true
+	}
+
+	a := &AuditLogger{cfg: cfg, redact: redact}
+	if err := a.open(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *AuditLogger) open() error {
+	if dir := filepath.Dir(a.cfg.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create audit log directory: %v", err)
+		}
+	}
+
+	f, err := os.OpenFile(a.cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat audit log: %v", err)
+	}
+
+	a.file = f
+	a.size = info.Size()
+	return nil
+}
+
+// rotate shifts Path.N-1 -> Path.N down to Path -> Path.1 and reopens Path.
+// The caller must hold a.mu.
+func (a *AuditLogger) rotate() error {
+	if err := a.file.Close(); err != nil {
+		return err
+	}
+
+	if a.cfg.MaxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", a.cfg.Path, a.cfg.MaxBackups))
+		for i := a.cfg.MaxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", a.cfg.Path, i), fmt.Sprintf("%s.%d", a.cfg.Path, i+1))
+		}
+		if err := os.Rename(a.cfg.Path, a.cfg.Path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Truncate(a.cfg.Path, 0); err != nil {
+		return err
+	}
+
+	return a.open()
+}
+
+// Write appends a single entry to the log.
+func (a *AuditLogger) Write(entry *auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %v", err)
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	maxSize := int64(a.cfg.MaxSizeMB) * 1024 * 1024
+	if maxSize > 0 && a.size+int64(len(line)) > maxSize && a.size > 0 {
+		if err := a.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %v", err)
+		}
+	}
+
+	n, err := a.file.Write(line)
+	a.size += int64(n)
+	return err
+}
+
+// Close flushes and closes the underlying file.
+func (a *AuditLogger) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.file.Sync(); err != nil {
+		return err
+	}
+	return a.file.Close()
+}
+
+// redactArguments returns a copy of args with sensitive keys masked.
+func (a *AuditLogger) redactArguments(args any) any {
+	switch v := args.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			if a.redact[strings.ToLower(key)] {
+				out[key] = redactedValue
+				continue
+			}
+			out[key] = a.redactArguments(value)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			if a.redact[strings.ToLower(key)] {
+				out[key] = redactedValue
+				continue
+			}
+			out[key] = value
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, value := range v {
+			out[i] = a.redactArguments(value)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// Middleware records one audit entry per JSON-RPC request posted to next.
+func (a *AuditLogger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entry := &auditEntry{
+			Time:       time.Now().UTC(),
+			SessionID:  r.Header.Get(server.HeaderKeySessionID),
+			RemoteAddr: r.RemoteAddr,
+			UserAgent:  r.UserAgent(),
+		}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), auditEntryKey{}, entry)))
+
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+		if entry.Method == "" {
+			// Responses to server-initiated requests and malformed bodies never
+			// reach the dispatcher; there is nothing useful to record.
+			return
+		}
+		entry.LatencyMs = float64(time.Since(entry.Time).Microseconds()) / 1000
+		entry.HTTPStatus = rec.status
+		if entry.SessionID == "" {
+			entry.SessionID = rec.Header().Get(server.HeaderKeySessionID)
+		}
+		if err := a.Write(entry); err != nil {
+			log.Printf("Audit log write failed: %v", err)
+		}
+	})
+}
+
+// RegisterHooks wires the logger into the MCP dispatcher so each entry learns
+// the method, target and outcome of its request.
+func (a *AuditLogger) RegisterHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		entry := auditEntryFromContext(ctx)
+		if entry == nil || isInternalRequest(ctx) {
+			return
+		}
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+
+		entry.RequestID = id
+		entry.Method = string(method)
+		if session := server.ClientSessionFromContext(ctx); session != nil && entry.SessionID == "" {
+			entry.SessionID = session.SessionID()
+		}
+
+		switch req := message.(type) {
+		case *mcp.CallToolRequest:
+			entry.Tool = req.Params.Name
+			if !a.cfg.OmitArguments {
+				entry.Arguments = a.redactArguments(req.Params.Arguments)
+			}
+		case *mcp.ReadResourceRequest:
+			entry.Resource = req.Params.URI
+		case *mcp.SubscribeRequest:
+			entry.Resource = req.Params.URI
+		case *mcp.UnsubscribeRequest:
+			entry.Resource = req.Params.URI
+		case *mcp.GetPromptRequest:
+			entry.Prompt = req.Params.Name
+			if !a.cfg.OmitArguments {
+				entry.Arguments = a.redactArguments(req.Params.Arguments)
+			}
+		case *mcp.CompleteRequest:
+			switch ref := req.Params.Ref.(type) {
+			case mcp.PromptReference:
+				entry.Prompt = ref.Name
+			case mcp.ResourceReference:
+				entry.Resource = ref.URI
+			}
+			if !a.cfg.OmitArguments {
+				entry.Arguments = map[string]any{"name": req.Params.Argument.Name, "value": req.Params.Argument.Value}
+			}
+		}
+	})
+
+	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
+		entry := auditEntryFromContext(ctx)
+		if entry == nil || isInternalRequest(ctx) {
+			return
+		}
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+
+		entry.Outcome = outcomeSuccess
+		if a.cfg.RecordResponses {
+			entry.Response = result
+		}
+		if res, ok := result.(*mcp.CallToolResult); ok && res.IsError {
+			entry.Outcome = outcomeToolError
+			entry.Error = toolErrorText(res)
+		}
+	})
+
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		entry := auditEntryFromContext(ctx)
+		if entry == nil || isInternalRequest(ctx) {
+			return
+		}
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+
+		if entry.Method == "" {
+			entry.Method = string(method)
+			entry.RequestID = id
+		}
+		entry.Outcome = outcomeError
+		entry.Error = err.Error()
+	})
+}
+
+// toolErrorText extracts the message from an IsError tool result.
+func toolErrorText(res *mcp.CallToolResult) string {
+	for _, content := range res.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// statusRecorder captures the status code written by the wrapped handler
+// while still supporting streaming responses.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,637 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// The calculate tool evaluates arithmetic with big.Rat, so sums, products
+// and quotients of decimals are exact: 0.1 + 0.2 is 0.3. Functions with
+// irrational results (sqrt, exp, sin, ...) and the constants pi and e are
+// computed in floating point and mark the result as approximate.
+
+const (
+	// defaultPrecision is the number of decimal places shown for results
+	// that don't terminate, such as 1/3.
+	defaultPrecision = 20
+	// maxExponent and maxResultBits keep every intermediate result small
+	// enough to compute and print quickly; maxResultBits bounds the
+	// numerator and denominator, so whole results have at most about
+	// 20000 digits.
+	maxExponent   = 10000
+	maxResultBits = 1 << 16
+	// maxPlaces is the most decimal places written for a terminating
+	// result; longer ones are rounded to the requested precision.
+	maxPlaces = 1000
+	// maxNesting bounds parenthesis and function call depth.
+	maxNesting = 100
+)
+
+// calculateArgs are the arguments of the calculate tool.
+type calculateArgs struct {
+	Expression string `json:"expression" validate:"required,max=1000" description:"Arithmetic expression, e.g. (1.1 + 2.2) * 3 or sqrt(2) / 2. Supports + - * / % ^ (or **), parentheses, the constants pi and e, and abs, ceil, floor, round, trunc, min, max, sqrt, exp, ln, log, log2, sin, cos, tan, asin, acos and atan"`
+	Precision  int    `json:"precision" validate:"min=1,max=1000" description:"Decimal places shown when the result does not terminate (default 20)"`
+}
+
+// calculateOutput is the structuredContent of calculate.
+type calculateOutput struct {
+	Expression string `json:"expression" validate:"required" description:"The expression as given"`
+	Result     string `json:"result" validate:"required" description:"The value as a decimal"`
+	Fraction   string `json:"fraction,omitempty" description:"The exact value as a reduced fraction, when it is not a whole number"`
+	Exact      bool   `json:"exact" description:"Whether result is exactly the value of the expression, neither rounded nor approximated"`
+}
+
+// CalcError is an invalid expression or a failed evaluation, such as a
+// division by zero, at a position in the expression.
+type CalcError struct {
+	Expression string `json:"expression"`
+	Position   int    `json:"position"` // 1-based, in characters
+	Message    string `json:"message"`
+}
+
+func (e *CalcError) Error() string {
+	return fmt.Sprintf("at position %d: %s", e.Position, e.Message)
+}
+
+// Result renders the error as a tool error result that points at the
+// offending character.
+func (e *CalcError) Result() *mcp.CallToolResult {
+	text := fmt.Sprintf("Invalid expression at position %d: %s\n  %s\n  %s^",
+		e.Position, e.Message, e.Expression, strings.Repeat(" ", e.Position-1))
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{mcp.NewTextContent(text)},
+		StructuredContent: e,
+		IsError:           true,
+	}
+}
+
+// calcValue is an intermediate result. exact is false once a floating
+// point function or constant was involved.
+type calcValue struct {
+	rat   *big.Rat
+	exact bool
+}
+
+func exactValue(r *big.Rat) calcValue { return calcValue{rat: r, exact: true} }
+
+// approxValue converts a float64 result, rejecting NaN and infinities.
+func approxValue(f float64) (calcValue, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return calcValue{}, fmt.Errorf("result is not a finite number")
+	}
+	// The shortest decimal that round-trips, so 0.1 stays 0.1.
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return calcValue{rat: r}, nil
+}
+
+func (v calcValue) float() float64 {
+	f, _ := v.rat.Float64()
+	return f
+}
+
+// calcConstants are approximations; pi and e are given to 50 digits.
+var calcConstants = map[string]string{
+	"pi": "3.14159265358979323846264338327950288419716939937510",
+	"e":  "2.71828182845904523536028747135266249775724709369995",
+}
+
+// calcFunc is a function callable in expressions.
+type calcFunc struct {
+	minArgs, maxArgs int // maxArgs < 0 means any number
+	eval             func(args []calcValue) (calcValue, error)
+}
+
+// floatFunc wraps a float64 function of one argument.
+func floatFunc(f func(float64) float64) calcFunc {
+	return calcFunc{1, 1, func(args []calcValue) (calcValue, error) {
+		return approxValue(f(args[0].float()))
+	}}
+}
+
+var calcFuncs map[string]calcFunc
+
+func init() {
+	calcFuncs = map[string]calcFunc{
+		"abs": {1, 1, func(args []calcValue) (calcValue, error) {
+			return calcValue{new(big.Rat).Abs(args[0].rat), args[0].exact}, nil
+		}},
+		"floor": {1, 1, func(args []calcValue) (calcValue, error) {
+			return calcValue{floorRat(args[0].rat), args[0].exact}, nil
+		}},
+		"ceil": {1, 1, func(args []calcValue) (calcValue, error) {
+			ceil := floorRat(new(big.Rat).Neg(args[0].rat))
+			return calcValue{ceil.Neg(ceil), args[0].exact}, nil
+		}},
+		"trunc": {1, 1, func(args []calcValue) (calcValue, error) {
+			return calcValue{truncRat(args[0].rat), args[0].exact}, nil
+		}},
+		"round": {1, 2, roundFunc},
+		"min":   {1, -1, func(args []calcValue) (calcValue, error) { return extreme(args, -1), nil }},
+		"max":   {1, -1, func(args []calcValue) (calcValue, error) { return extreme(args, 1), nil }},
+		"sqrt":  {1, 1, sqrtFunc},
+		"exp":   floatFunc(math.Exp),
+		"ln":    floatFunc(math.Log),
+		"log2":  floatFunc(math.Log2),
+		"log": {1, 2, func(args []calcValue) (calcValue, error) {
+			if len(args) == 2 {
+				return approxValue(math.Log(args[0].float()) / math.Log(args[1].float()))
+			}
+			return approxValue(math.Log10(args[0].float()))
+		}},
+		"sin":  floatFunc(math.Sin),
+		"cos":  floatFunc(math.Cos),
+		"tan":  floatFunc(math.Tan),
+		"asin": floatFunc(math.Asin),
+		"acos": floatFunc(math.Acos),
+		"atan": floatFunc(math.Atan),
+	}
+}
+
+// floorRat rounds r down to an integer. Denominators are always positive,
+// so Euclidean division is floor division.
+func floorRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Div(r.Num(), r.Denom()))
+}
+
+// truncRat rounds r towards zero.
+func truncRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Quo(r.Num(), r.Denom()))
+}
+
+// roundFunc rounds half away from zero, to a number of decimal places if
+// given.
+func roundFunc(args []calcValue) (calcValue, error) {
+	places := int64(0)
+	exact := args[0].exact
+	if len(args) == 2 {
+		if !args[1].rat.IsInt() || args[1].rat.Num().CmpAbs(big.NewInt(1000)) > 0 {
+			return calcValue{}, fmt.Errorf("round places must be a whole number between -1000 and 1000")
+		}
+		places = args[1].rat.Num().Int64()
+		exact = exact && args[1].exact
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs64(places)), nil))
+	if places < 0 {
+		scale.Inv(scale)
+	}
+	scaled := new(big.Rat).Mul(new(big.Rat).Abs(args[0].rat), scale)
+	rounded := floorRat(scaled.Add(scaled, big.NewRat(1, 2)))
+	if args[0].rat.Sign() < 0 {
+		rounded.Neg(rounded)
+	}
+	return calcValue{rounded.Quo(rounded, scale), exact}, nil
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// extreme returns the smallest (sign -1) or largest (sign 1) argument.
+func extreme(args []calcValue, sign int) calcValue {
+	best := args[0]
+	for _, v := range args[1:] {
+		if v.rat.Cmp(best.rat) == sign {
+			best = v
+		}
+	}
+	return best
+}
+
+// sqrtFunc is exact for squares of rationals, such as sqrt(2.25), and
+// computed to 50 significant digits otherwise.
+func sqrtFunc(args []calcValue) (calcValue, error) {
+	x := args[0].rat
+	if x.Sign() < 0 {
+		return calcValue{}, fmt.Errorf("square root of a negative number")
+	}
+	num, den := new(big.Int).Sqrt(x.Num()), new(big.Int).Sqrt(x.Denom())
+	if new(big.Int).Mul(num, num).Cmp(x.Num()) == 0 && new(big.Int).Mul(den, den).Cmp(x.Denom()) == 0 {
+		return calcValue{new(big.Rat).SetFrac(num, den), args[0].exact}, nil
+	}
+	f := new(big.Float).SetPrec(256).SetRat(x)
+	r, _ := new(big.Rat).SetString(f.Sqrt(f).Text('g', 50))
+	return calcValue{rat: r}, nil
+}
+
+// power raises base to exp: exactly for whole exponents, in floating point
+// otherwise.
+func power(base, exp calcValue) (calcValue, error) {
+	exact := base.exact && exp.exact
+	if !exp.rat.IsInt() {
+		if base.rat.Sign() < 0 {
+			return calcValue{}, fmt.Errorf("fractional power of a negative number")
+		}
+		return approxValue(math.Pow(base.float(), exp.float()))
+	}
+	n := exp.rat.Num()
+	if n.CmpAbs(big.NewInt(maxExponent)) > 0 {
+		return calcValue{}, fmt.Errorf("exponent is larger than %d", maxExponent)
+	}
+	k := abs64(n.Int64())
+	bits := int64(max(base.rat.Num().BitLen(), base.rat.Denom().BitLen()))
+	if bits*k > maxResultBits {
+		return calcValue{}, fmt.Errorf("result is too large")
+	}
+	if n.Sign() < 0 && base.rat.Sign() == 0 {
+		return calcValue{}, fmt.Errorf("division by zero")
+	}
+	kk := big.NewInt(k)
+	result := new(big.Rat).SetFrac(
+		new(big.Int).Exp(base.rat.Num(), kk, nil),
+		new(big.Int).Exp(base.rat.Denom(), kk, nil),
+	)
+	if n.Sign() < 0 {
+		result.Inv(result)
+	}
+	return calcValue{result, exact}, nil
+}
+
+// checkSize rejects v if it has grown past maxResultBits.
+func checkSize(v calcValue) error {
+	if v.rat.Num().BitLen() > maxResultBits || v.rat.Denom().BitLen() > maxResultBits {
+		return fmt.Errorf("result is too large")
+	}
+	return nil
+}
+
+// calcParser evaluates an expression by recursive descent:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "%") unary }
+//	unary   = ("+" | "-") unary | power
+//	power   = primary [ ("^" | "**") unary ]
+//	primary = number | name | name "(" expr { "," expr } ")" | "(" expr ")"
+//
+// so -2^2 is -4 and 2^3^2 is 2^9.
+type calcParser struct {
+	src []rune
+	pos int
+}
+
+// evaluate parses and evaluates expression. Errors are *CalcError.
+func evaluate(expression string) (calcValue, error) {
+	p := &calcParser{src: []rune(expression)}
+	p.skipSpace()
+	if p.pos == len(p.src) {
+		return calcValue{}, p.errorAt(0, "expression is empty")
+	}
+	v, err := p.expr(0)
+	if err != nil {
+		return calcValue{}, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return calcValue{}, p.errorAt(p.pos, "unexpected %q", p.src[p.pos])
+	}
+	return v, nil
+}
+
+func (p *calcParser) errorAt(pos int, format string, args ...any) *CalcError {
+	return &CalcError{Expression: string(p.src), Position: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *calcParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space character, or 0 at the end.
+func (p *calcParser) peek() rune {
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+// peekPower reports whether ** follows.
+func (p *calcParser) peekPower() bool {
+	return p.peek() == '*' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '*'
+}
+
+func (p *calcParser) expr(depth int) (calcValue, error) {
+	left, err := p.term(depth)
+	if err != nil {
+		return left, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		opPos := p.pos
+		p.pos++
+		right, err := p.term(depth)
+		if err != nil {
+			return right, err
+		}
+		result := new(big.Rat)
+		if op == '+' {
+			result.Add(left.rat, right.rat)
+		} else {
+			result.Sub(left.rat, right.rat)
+		}
+		left = calcValue{result, left.exact && right.exact}
+		if err := checkSize(left); err != nil {
+			return left, p.errorAt(opPos, "%v", err)
+		}
+	}
+}
+
+func (p *calcParser) term(depth int) (calcValue, error) {
+	left, err := p.unary(depth)
+	if err != nil {
+		return left, err
+	}
+	for {
+		op := p.peek()
+		if (op != '*' && op != '/' && op != '%') || p.peekPower() {
+			return left, nil
+		}
+		opPos := p.pos
+		p.pos++
+		right, err := p.unary(depth)
+		if err != nil {
+			return right, err
+		}
+		if op != '*' && right.rat.Sign() == 0 {
+			return right, p.errorAt(opPos, "division by zero")
+		}
+		result := new(big.Rat)
+		switch op {
+		case '*':
+			result.Mul(left.rat, right.rat)
+		case '/':
+			result.Quo(left.rat, right.rat)
+		case '%':
+			// The remainder has the sign of the dividend, as in Go.
+			quotient := truncRat(new(big.Rat).Quo(left.rat, right.rat))
+			result.Sub(left.rat, quotient.Mul(quotient, right.rat))
+		}
+		left = calcValue{result, left.exact && right.exact}
+		if err := checkSize(left); err != nil {
+			return left, p.errorAt(opPos, "%v", err)
+		}
+	}
+}
+
+func (p *calcParser) unary(depth int) (calcValue, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.unary(depth)
+		if err != nil {
+			return v, err
+		}
+		return calcValue{new(big.Rat).Neg(v.rat), v.exact}, nil
+	case '+':
+		p.pos++
+		return p.unary(depth)
+	}
+	return p.power(depth)
+}
+
+func (p *calcParser) power(depth int) (calcValue, error) {
+	base, err := p.primary(depth)
+	if err != nil {
+		return base, err
+	}
+	isCaret, isStars := p.peek() == '^', p.peekPower()
+	opPos := p.pos
+	switch {
+	case isCaret:
+		p.pos++
+	case isStars:
+		p.pos += 2
+	default:
+		return base, nil
+	}
+	exp, err := p.unary(depth)
+	if err != nil {
+		return exp, err
+	}
+	result, err := power(base, exp)
+	if err != nil {
+		return result, p.errorAt(opPos, "%v", err)
+	}
+	return result, nil
+}
+
+func (p *calcParser) primary(depth int) (calcValue, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return calcValue{}, p.errorAt(p.pos, "unexpected end of expression")
+	case c == '(':
+		return p.parenthesized(depth)
+	case c == '.' || unicode.IsDigit(c):
+		return p.number()
+	case unicode.IsLetter(c):
+		return p.name(depth)
+	default:
+		return calcValue{}, p.errorAt(p.pos, "unexpected %q", c)
+	}
+}
+
+func (p *calcParser) parenthesized(depth int) (calcValue, error) {
+	open := p.pos
+	if depth >= maxNesting {
+		return calcValue{}, p.errorAt(open, "expression is nested too deeply")
+	}
+	p.pos++
+	v, err := p.expr(depth + 1)
+	if err != nil {
+		return v, err
+	}
+	if p.peek() != ')' {
+		return v, p.errorAt(p.pos, "missing ')' for '(' at position %d", open+1)
+	}
+	p.pos++
+	return v, nil
+}
+
+// number reads a decimal such as 12, 0.5, .5 or 1.5e-3.
+func (p *calcParser) number() (calcValue, error) {
+	start := p.pos
+	digits := p.digits()
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		p.pos++
+		digits += p.digits()
+	}
+	if digits == 0 {
+		return calcValue{}, p.errorAt(start, "malformed number")
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		expStart := p.pos
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		if p.digits() == 0 {
+			return calcValue{}, p.errorAt(expStart, "malformed exponent")
+		}
+		exp, err := strconv.Atoi(string(p.src[expStart+1 : p.pos]))
+		if err != nil || exp > maxExponent || exp < -maxExponent {
+			return calcValue{}, p.errorAt(expStart, "exponent is larger than %d", maxExponent)
+		}
+	}
+	r, ok := new(big.Rat).SetString(string(p.src[start:p.pos]))
+	if !ok {
+		return calcValue{}, p.errorAt(start, "malformed number")
+	}
+	return exactValue(r), nil
+}
+
+func (p *calcParser) digits() int {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos - start
+}
+
+// name reads a constant or a function call.
+func (p *calcParser) name(depth int) (calcValue, error) {
+	start := p.pos
+	for p.pos < len(p.src) && (unicode.IsLetter(p.src[p.pos]) || unicode.IsDigit(p.src[p.pos])) {
+		p.pos++
+	}
+	name := strings.ToLower(string(p.src[start:p.pos]))
+
+	if p.peek() != '(' {
+		if value, ok := calcConstants[name]; ok {
+			r, _ := new(big.Rat).SetString(value)
+			return calcValue{rat: r}, nil
+		}
+		if _, ok := calcFuncs[name]; ok {
+			return calcValue{}, p.errorAt(p.pos, "expected '(' after %s", name)
+		}
+		return calcValue{}, p.errorAt(start, "unknown name %q", name)
+	}
+	fn, ok := calcFuncs[name]
+	if !ok {
+		return calcValue{}, p.errorAt(start, "unknown function %q", name)
+	}
+	if depth >= maxNesting {
+		return calcValue{}, p.errorAt(start, "expression is nested too deeply")
+	}
+
+	open := p.pos
+	p.pos++
+	var args []calcValue
+	if p.peek() != ')' {
+		for {
+			arg, err := p.expr(depth + 1)
+			if err != nil {
+				return arg, err
+			}
+			args = append(args, arg)
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+	}
+	if p.peek() != ')' {
+		return calcValue{}, p.errorAt(p.pos, "missing ')' for '(' at position %d", open+1)
+	}
+	p.pos++
+
+	switch {
+	case len(args) < fn.minArgs:
+		return calcValue{}, p.errorAt(start, "%s takes at least %d argument(s), got %d", name, fn.minArgs, len(args))
+	case fn.maxArgs >= 0 && len(args) > fn.maxArgs:
+		return calcValue{}, p.errorAt(start, "%s takes at most %d argument(s), got %d", name, fn.maxArgs, len(args))
+	}
+	result, err := fn.eval(args)
+	if err == nil {
+		err = checkSize(result)
+	}
+	if err != nil {
+		return result, p.errorAt(start, "%s: %v", name, err)
+	}
+	return result, nil
+}
+
+// formatRat writes r as a decimal. Terminating decimals of up to maxPlaces
+// places are written in full; others are rounded to precision places and
+// reported as rounded.
+func formatRat(r *big.Rat, precision int) (text string, rounded bool) {
+	if r.IsInt() {
+		return r.Num().String(), false
+	}
+	den := new(big.Int).Set(r.Denom())
+	twos := den.TrailingZeroBits()
+	den.Rsh(den, twos)
+	fives := uint(0)
+	five, rem := big.NewInt(5), new(big.Int)
+	for {
+		quo, _ := new(big.Int).QuoRem(den, five, rem)
+		if rem.Sign() != 0 {
+			break
+		}
+		den, fives = quo, fives+1
+	}
+	if places := int(max(twos, fives)); den.Cmp(big.NewInt(1)) == 0 && places <= maxPlaces {
+		return r.FloatString(places), false
+	}
+	text = strings.TrimRight(r.FloatString(precision), "0")
+	return strings.TrimSuffix(text, "."), true
+}
+
+// registerCalculator adds the calculate tool.
+func registerCalculator(s *server.MCPServer) {
+	calculateTool := mcp.NewTool("calculate",
+		mcp.WithDescription("Evaluates an arithmetic expression exactly, using arbitrary precision decimals. Use it instead of doing arithmetic yourself."),
+		withHints(toolHints{Title: "Calculator", ReadOnly: true, Idempotent: true}),
+		withArguments[calculateArgs](),
+		withOutput[calculateOutput](),
+	)
+
+	s.AddTool(calculateTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args calculateArgs) (*mcp.CallToolResult, error) {
+		value, err := evaluate(args.Expression)
+		if err != nil {
+			if calcErr, ok := err.(*CalcError); ok {
+				return calcErr.Result(), nil
+			}
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		precision := args.Precision
+		if precision == 0 {
+			precision = defaultPrecision
+		}
+		result, rounded := formatRat(value.rat, precision)
+		output := calculateOutput{
+			Expression: args.Expression,
+			Result:     result,
+			Exact:      value.exact && !rounded,
+		}
+		if value.exact && !value.rat.IsInt() {
+			output.Fraction = value.rat.RatString()
+		}
+
+		var text string
+		switch {
+		case output.Exact:
+			text = fmt.Sprintf("%s = %s", args.Expression, result)
+		case output.Fraction != "":
+			text = fmt.Sprintf("%s = %s ≈ %s", args.Expression, output.Fraction, result)
+		default:
+			text = fmt.Sprintf("%s ≈ %s", args.Expression, result)
+		}
+		return mcp.NewToolResultStructured(output, text), nil
+	}))
+}
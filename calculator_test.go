@@ -0,0 +1,136 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+		exact      bool
+	}{
+		{"0.1 + 0.2", "0.3", true},
+		{"(1.1 + 2.2) * 3", "9.9", true},
+		{"1/3", "0.33333333333333333333", false},
+		{"1/8", "0.125", true},
+		{"-2^2", "-4", true},
+		{"2^3^2", "512", true},
+		{"2**-2", "0.25", true},
+		{"7 % -3", "1", true},
+		{"-7 % 3", "-1", true},
+		{"1.5e3 + .5", "1500.5", true},
+		{"round(2.5) + round(-2.5)", "0", true},
+		{"round(3.14159, 2)", "3.14", true},
+		{"sqrt(2.25)", "1.5", true},
+		{"max(1, 3, 2) - min(4, 5)", "-1", true},
+		{"floor(-1.5) + ceil(1.2) + trunc(-1.7)", "-1", true},
+		{"sqrt(2)", "1.4142135623730950488016887242096980785696718753769", false},
+		{"2 * pi", "6.2831853071795864769252867665590057683943387987502", false},
+		{"2^-1100", "0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			v, err := evaluate(tt.expression)
+			if err != nil {
+				t.Fatalf("evaluate(%q): %v", tt.expression, err)
+			}
+			got, rounded := formatRat(v.rat, defaultPrecision)
+			if got != tt.want || (v.exact && !rounded) != tt.exact {
+				t.Errorf("evaluate(%q) = %s (exact %v), want %s (exact %v)", tt.expression, got, v.exact && !rounded, tt.want, tt.exact)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		position   int
+		message    string
+	}{
+		{"", 1, "expression is empty"},
+		{"1 + (2 * 3", 11, "missing ')' for '(' at position 5"},
+		{"1 / (2 - 2)", 3, "division by zero"},
+		{"5 % 0", 3, "division by zero"},
+		{"2 $ 3", 3, `unexpected '$'`},
+		{"1 +", 4, "unexpected end of expression"},
+		{"foo(1)", 1, `unknown function "foo"`},
+		{"sqrt", 5, "expected '(' after sqrt"},
+		{"sqrt(-1)", 1, "sqrt: square root of a negative number"},
+		{"min()", 1, "min takes at least 1 argument(s), got 0"},
+		{"1.2.3", 4, `unexpected '.'`},
+		{"1e", 2, "malformed exponent"},
+		{"(-8)^0.5", 5, "fractional power of a negative number"},
+		{"2^10001", 2, "exponent is larger than 10000"},
+		{"10^10000 * 10^10000", 10, "result is too large"},
+		{"1e10000 * 1e10000", 9, "result is too large"},
+		{"1 / 1e10000 / 1e10000", 13, "result is too large"},
+		{"4^9000 * 4^9000 * 4^9000 * 4^9000", 26, "result is too large"},
+		{"abs(1e10000 * 1e10000)", 13, "result is too large"},
+		{strings.Repeat("(", 101) + "1" + strings.Repeat(")", 101), 101, "expression is nested too deeply"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := evaluate(tt.expression)
+			calcErr, ok := err.(*CalcError)
+			if !ok {
+				t.Fatalf("evaluate(%q) error = %v, want *CalcError", tt.expression, err)
+			}
+			if calcErr.Position != tt.position || calcErr.Message != tt.message {
+				t.Errorf("evaluate(%q) = position %d %q, want position %d %q", tt.expression, calcErr.Position, calcErr.Message, tt.position, tt.message)
+			}
+		})
+	}
+}
+
+// TestEvaluateLimits checks that long chains of operations within the
+// expression length limit stay fast and produce bounded output.
+func TestEvaluateLimits(t *testing.T) {
+	for _, expression := range []string{
+		"9^9999" + strings.Repeat(" * 9^9999", 60),
+		"1e9999" + strings.Repeat(" / 7e9999", 60),
+		"7^9000 / 3^9000" + strings.Repeat(" * 1.1", 160),
+		"2^-65000" + strings.Repeat(" * 3", 200),
+	} {
+		expression = expression[:min(len(expression), 1000)]
+		start := time.Now()
+		v, err := evaluate(expression)
+		if err == nil {
+			text, _ := formatRat(v.rat, 1000)
+			if len(text) > 25000 {
+				t.Errorf("evaluate(%.20q...) has %d characters", expression, len(text))
+			}
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("evaluate(%.20q...) took %v", expression, elapsed)
+		}
+	}
+}
+
+func TestFormatRat(t *testing.T) {
+	tests := []struct {
+		expression string
+		precision  int
+		want       string
+		rounded    bool
+	}{
+		{"42", 2, "42", false},
+		{"1/4", 1, "0.25", false},
+		{"2/3", 3, "0.667", true},
+		{"1/6", 5, "0.16667", true},
+		{"1/2^1001", 20, "0", true},
+	}
+	for _, tt := range tests {
+		v, err := evaluate(tt.expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, rounded := formatRat(v.rat, tt.precision)
+		if got != tt.want || rounded != tt.rounded {
+			t.Errorf("formatRat(%s, %d) = %q, %v; want %q, %v", tt.expression, tt.precision, got, rounded, tt.want, tt.rounded)
+		}
+	}
+}
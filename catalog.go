@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Product is one entry of the product catalog, in the shape the
+// list-products widget reads from structuredContent.
+type Product struct {
+	Name        string `json:"name" validate:"required" description:"Display name"`
+	Price       string `json:"price" validate:"required,pattern=^[0-9]+\\.[0-9]{2}$" description:"Price in USD with two decimals, e.g. \"49.99\""`
+	PriceID     string `json:"priceId" validate:"required" description:"Stable identifier used to order the product"`
+	Description string `json:"description" validate:"required" description:"One-line summary"`
+	Category    string `json:"category" validate:"required" description:"Catalog category, e.g. \"plans\""`
+	Image       string `json:"image" description:"Thumbnail URL"`
+	// Features maps catalogFeatures keys to this product's value; features
+	// the product lacks are left out.
+	Features map[string]string `json:"features,omitempty" description:"Included features by key, e.g. {\"storage\": \"100 GB\"}"`
+
+	// The details below are left out of listings; see summary.
+	Variants   []ProductVariant `json:"variants,omitempty" description:"Purchasable variants by tier and billing period"`
+	Images     []ProductImage   `json:"images,omitempty" description:"Gallery images, the first being the main one"`
+	Highlights []string         `json:"highlights,omitempty" description:"Key features as bullet points"`
+	FAQs       []ProductFAQ     `json:"faqs,omitempty" description:"Frequently asked questions"`
+}
+
+// ProductVariant is one purchasable tier and billing period of a product.
+// The default variant shares the product's price ID.
+type ProductVariant struct {
+	PriceID       string `json:"priceId" validate:"required" description:"Identifier used to order this variant"`
+	Tier          string `json:"tier" validate:"required" description:"Tier name, e.g. \"Premium Plus\""`
+	BillingPeriod string `json:"billingPeriod" validate:"required,enum=monthly|yearly" description:"How often the price is charged"`
+	Price         string `json:"price" validate:"required,pattern=^[0-9]+\\.[0-9]{2}$" description:"Price in USD per billing period"`
+}
+
+// ProductImage is one image of a product's gallery.
+type ProductImage struct {
+	URL string `json:"url" validate:"required" description:"Image URL"`
+	Alt string `json:"alt" description:"Alternative text"`
+}
+
+// ProductFAQ is a frequently asked question about a product.
+type ProductFAQ struct {
+	Question string `json:"question" validate:"required" description:"The question"`
+	Answer   string `json:"answer" validate:"required" description:"The answer"`
+}
+
+// summary returns p without its details, for listings.
+func (p Product) summary() Product {
+	p.Variants, p.Images, p.Highlights, p.FAQs = nil, nil, nil, nil
+	return p
+}
+
+// catalogSummaries returns the summary of every product.
+func catalogSummaries() []Product {
+	products := make([]Product, len(catalogProducts))
+	for i, p := range catalogProducts {
+		products[i] = p.summary()
+	}
+	return products
+}
+
+// catalogFeature is a feature products are compared on.
+type catalogFeature struct {
+	Key   string
+	Label string
+}
+
+// catalogFeatures are the features in comparison order.
+var catalogFeatures = []catalogFeature{
+	{"users", "Users"},
+	{"storage", "Storage"},
+	{"support", "Support"},
+	{"api_access", "API access"},
+	{"analytics", "Advanced analytics"},
+	{"sso", "Single sign-on"},
+	{"custom_features", "Custom features"},
+	{"sla", "Uptime SLA"},
+}
+
+// catalogProducts is the product catalog served by list_products.
+var catalogProducts = []Product{
+	{
+		Name:        "Premium Widget",
+		Price:       "99.99",
+		PriceID:     "price_premium_widget",
+		Description: "Our flagship product with advanced features and premium support",
+		Category:    "widgets",
+		Image:       "https://images.unsplash.com/photo-1526374965328-7f61d4dc18c5?w=150&h=150&fit=crop",
+		Features: map[string]string{
+			"users":      "Up to 50",
+			"storage":    "1 TB",
+			"support":    "Priority (24h response)",
+			"api_access": "Included",
+			"analytics":  "Included",
+			"sso":        "Included",
+			"sla":        "99.9%",
+		},
+		Variants: []ProductVariant{
+			{PriceID: "price_premium_widget", Tier: "Premium", BillingPeriod: "monthly", Price: "99.99"},
+			{PriceID: "price_premium_widget_yearly", Tier: "Premium", BillingPeriod: "yearly", Price: "999.99"},
+			{PriceID: "price_premium_widget_plus", Tier: "Premium Plus", BillingPeriod: "monthly", Price: "149.99"},
+			{PriceID: "price_premium_widget_plus_yearly", Tier: "Premium Plus", BillingPeriod: "yearly", Price: "1499.99"},
+		},
+		Images: []ProductImage{
+			{URL: "https://images.unsplash.com/photo-1526374965328-7f61d4dc18c5?w=600&h=400&fit=crop", Alt: "Premium Widget dashboard"},
+			{URL: "https://images.unsplash.com/photo-1551288049-bebda4e38f71?w=600&h=400&fit=crop", Alt: "Advanced analytics charts"},
+			{URL: "https://images.unsplash.com/photo-1460925895917-afdab827c52f?w=600&h=400&fit=crop", Alt: "Team reporting view"},
+		},
+		Highlights: []string{
+			"Advanced analytics with custom dashboards",
+			"Single sign-on for up to 50 users",
+			"Priority support with a 24 hour response time",
+			"99.9% uptime SLA",
+		},
+		FAQs: []ProductFAQ{
+			{Question: "What does Premium Plus add?", Answer: "Premium Plus adds audit logs, sandbox environments and a named support engineer."},
+			{Question: "Can I switch to yearly billing later?", Answer: "Yes. Switching to yearly billing takes effect at your next renewal and saves about two months a year."},
+			{Question: "Is there a free trial?", Answer: "Every Premium tier comes with a 14 day free trial, no credit card required."},
+		},
+	},
+	{
+		Name:        "Standard Package",
+		Price:       "49.99",
+		PriceID:     "price_standard_package",
+		Description: "Perfect for small teams with essential features included",
+		Category:    "plans",
+		Image:       "https://images.unsplash.com/photo-1460925895917-afdab827c52f?w=150&h=150&fit=crop",
+		Features: map[string]string{
+			"users":      "Up to 10",
+			"storage":    "100 GB",
+			"support":    "Email",
+			"api_access": "Included",
+			"sla":        "99.5%",
+		},
+		Variants: []ProductVariant{
+			{PriceID: "price_standard_package", Tier: "Standard", BillingPeriod: "monthly", Price: "49.99"},
+			{PriceID: "price_standard_package_yearly", Tier: "Standard", BillingPeriod: "yearly", Price: "499.99"},
+		},
+		Images: []ProductImage{
+			{URL: "https://images.unsplash.com/photo-1460925895917-afdab827c52f?w=600&h=400&fit=crop", Alt: "Standard Package overview"},
+			{URL: "https://images.unsplash.com/photo-1484480974693-6ca0a78fb36b?w=600&h=400&fit=crop", Alt: "Task planning"},
+		},
+		Highlights: []string{
+			"Up to 10 users with shared workspaces",
+			"100 GB of storage",
+			"API access for integrations",
+			"Email support",
+		},
+		FAQs: []ProductFAQ{
+			{Question: "What happens if my team grows past 10 users?", Answer: "You can upgrade to Premium Widget at any time; unused days are credited."},
+			{Question: "Does the API have rate limits?", Answer: "Standard includes 1,000 API requests per minute."},
+		},
+	},
+	{
+		Name:        "Basic Starter",
+		Price:       "29.99",
+		PriceID:     "price_basic_starter",
+		Description: "Get started with our basic plan, ideal for individuals",
+		Category:    "plans",
+		Image:       "https://images.unsplash.com/photo-1484480974693-6ca0a78fb36b?w=150&h=150&fit=crop",
+		Features: map[string]string{
+			"users":   "1",
+			"storage": "10 GB",
+			"support": "Community",
+		},
+		Variants: []ProductVariant{
+			{PriceID: "price_basic_starter", Tier: "Basic", BillingPeriod: "monthly", Price: "29.99"},
+			{PriceID: "price_basic_starter_yearly", Tier: "Basic", BillingPeriod: "yearly", Price: "299.99"},
+		},
+		Images: []ProductImage{
+			{URL: "https://images.unsplash.com/photo-1484480974693-6ca0a78fb36b?w=600&h=400&fit=crop", Alt: "Basic Starter workspace"},
+		},
+		Highlights: []string{
+			"Everything one person needs to get started",
+			"10 GB of storage",
+			"Community forum support",
+		},
+		FAQs: []ProductFAQ{
+			{Question: "Can I add more users?", Answer: "Basic Starter is for one user. Standard Package supports up to 10."},
+			{Question: "Can I cancel anytime?", Answer: "Yes. Monthly plans can be cancelled at any time and stay active until the end of the period."},
+		},
+	},
+	{
+		Name:        "Enterprise Solution",
+		Price:       "199.99",
+		PriceID:     "price_enterprise_solution",
+		Description: "Complete enterprise solution with dedicated support and custom features",
+		Category:    "enterprise",
+		Image:       "https://images.unsplash.com/photo-1551288049-bebda4e38f71?w=150&h=150&fit=crop",
+		Features: map[string]string{
+			"users":           "Unlimited",
+			"storage":         "Unlimited",
+			"support":         "Dedicated account manager",
+			"api_access":      "Included",
+			"analytics":       "Included",
+			"sso":             "Included",
+			"custom_features": "Included",
+			"sla":             "99.99%",
+		},
+		Variants: []ProductVariant{
+			{PriceID: "price_enterprise_solution", Tier: "Enterprise", BillingPeriod: "monthly", Price: "199.99"},
+			{PriceID: "price_enterprise_solution_yearly", Tier: "Enterprise", BillingPeriod: "yearly", Price: "1999.99"},
+		},
+		Images: []ProductImage{
+			{URL: "https://images.unsplash.com/photo-1551288049-bebda4e38f71?w=600&h=400&fit=crop", Alt: "Enterprise analytics"},
+			{URL: "https://images.unsplash.com/photo-1526374965328-7f61d4dc18c5?w=600&h=400&fit=crop", Alt: "Custom integrations"},
+			{URL: "https://images.unsplash.com/photo-1460925895917-afdab827c52f?w=600&h=400&fit=crop", Alt: "Organization-wide reporting"},
+		},
+		Highlights: []string{
+			"Unlimited users and storage",
+			"Custom features built with our team",
+			"Dedicated account manager",
+			"99.99% uptime SLA",
+		},
+		FAQs: []ProductFAQ{
+			{Question: "How are custom features scoped?", Answer: "Your account manager runs a scoping workshop and delivers a plan within two weeks."},
+			{Question: "Do you support on-premises deployment?", Answer: "Yes, as part of a yearly Enterprise agreement."},
+			{Question: "Which compliance reports are available?", Answer: "SOC 2 Type II and ISO 27001 reports are available under NDA."},
+		},
+	},
+}
+
+// Resource templates for the catalog.
+const (
+	productTemplate  = "product://{priceId}"
+	categoryTemplate = "catalog://category/{name}"
+)
+
+// findVariant returns the product with a variant of priceID, and the
+// variant.
+func findVariant(priceID string) (Product, ProductVariant, bool) {
+	for _, p := range catalogProducts {
+		for _, v := range p.Variants {
+			if v.PriceID == priceID {
+				return p, v, true
+			}
+		}
+	}
+	return Product{}, ProductVariant{}, false
+}
+
+// findProduct returns the product with priceID.
+func findProduct(priceID string) (Product, bool) {
+	for _, p := range catalogProducts {
+		if p.PriceID == priceID {
+			return p, true
+		}
+	}
+	return Product{}, false
+}
+
+// findProductOrVariant returns the product with priceID, or the product
+// one of whose variants has it.
+func findProductOrVariant(priceID string) (Product, bool) {
+	if product, ok := findProduct(priceID); ok {
+		return product, true
+	}
+	product, _, ok := findVariant(priceID)
+	return product, ok
+}
+
+// productsInCategory returns the products in category, ignoring case.
+func productsInCategory(category string) []Product {
+	var products []Product
+	for _, p := range catalogProducts {
+		if strings.EqualFold(p.Category, category) {
+			products = append(products, p)
+		}
+	}
+	return products
+}
+
+// catalogCategories returns the category names, sorted.
+func catalogCategories() []string {
+	var categories []string
+	for _, p := range catalogProducts {
+		if !slices.Contains(categories, p.Category) {
+			categories = append(categories, p.Category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// catalogPriceIDs returns the price IDs of every product.
+func catalogPriceIDs() []string {
+	ids := make([]string, len(catalogProducts))
+	for i, p := range catalogProducts {
+		ids[i] = p.PriceID
+	}
+	return ids
+}
+
+// categoryContents is the JSON of a catalog://category/{name} resource.
+type categoryContents struct {
+	Category string    `json:"category"`
+	Products []Product `json:"products"`
+}
+
+// registerCatalog adds the product and category resource templates, so a
+// client can attach a product or category to its context without calling
+// a tool.
+func registerCatalog(s *server.MCPServer, completer *Completer) {
+	s.AddResourceTemplate(mcp.NewResourceTemplate(productTemplate, "Product",
+		mcp.WithTemplateDescription("A catalog product as JSON, by its own or a variant's price ID"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		priceID := templateVariable(request, "priceId")
+		done := traceLookup(ctx, "catalog.product", attribute.String("catalog.price_id", priceID))
+		product, ok := findProductOrVariant(priceID)
+		done(ok)
+		if !ok {
+			return nil, fmt.Errorf("product %q not found", priceID)
+		}
+		return jsonResource(request.Params.URI, product)
+	})
+	completer.Resource(productTemplate, "priceId", completeFrom(catalogPriceIDs()...))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(categoryTemplate, "Catalog Category",
+		mcp.WithTemplateDescription("The products in a catalog category as JSON"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		name := templateVariable(request, "name")
+		done := traceLookup(ctx, "catalog.category", attribute.String("catalog.category", name))
+		products := productsInCategory(name)
+		done(len(products) > 0)
+		if len(products) == 0 {
+			return nil, fmt.Errorf("category %q not found; categories are %s", name, strings.Join(catalogCategories(), ", "))
+		}
+		return jsonResource(request.Params.URI, categoryContents{Category: products[0].Category, Products: products})
+	})
+	completer.Resource(categoryTemplate, "name", completeFrom(catalogCategories()...))
+}
+
+// jsonResource returns v as the application/json contents of uri.
+func jsonResource(uri string, v any) ([]mcp.ResourceContents, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %v", uri, err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     buf.String(),
+		},
+	}, nil
+}
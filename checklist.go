@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// checklistTemplate is the URI template of the code review checklists.
+const checklistTemplate = "review://checklist/{language}"
+
+// reviewSeverities ranks the severities a review reports, highest first.
+var reviewSeverities = []string{"critical", "major", "minor", "nit"}
+
+// checklistItem is one thing a reviewer should check, with the severity an
+// issue it finds usually has.
+type checklistItem struct {
+	Severity string
+	Text     string
+}
+
+// generalChecklist applies to every language.
+var generalChecklist = []checklistItem{
+	{"critical", "Untrusted input reaches queries, shell commands, file paths or templates without validation or escaping"},
+	{"critical", "Secrets, tokens or personal data are hard-coded or written to logs"},
+	{"major", "Errors are ignored, swallowed or reported without enough context to act on"},
+	{"major", "Resources (files, connections, locks) are released on every path, including errors"},
+	{"major", "Behaviour changes are covered by tests, including failure cases"},
+	{"minor", "Names say what things are; functions do one thing"},
+	{"minor", "Work inside loops that could be hoisted or batched"},
+	{"nit", "Comments explain why, not what, and match the code"},
+}
+
+// languageChecklists holds the language-specific items, keyed by the
+// checklist name used in the URI.
+var languageChecklists = map[string]struct {
+	Title string
+	Items []checklistItem
+}{
+	"go": {"Go", []checklistItem{
+		{"critical", "Goroutines can leak: every goroutine has a way to stop, usually a context"},
+		{"critical", "Shared state is guarded by a mutex or owned by one goroutine; run with -race"},
+		{"major", "Errors are wrapped with %w where callers need errors.Is or errors.As"},
+		{"major", "defer inside loops, and deferred Close errors on writable files"},
+		{"minor", "Interfaces are declared where they are used and kept small"},
+		{"nit", "gofmt, and doc comments on exported names start with the name"},
+	}},
+	"python": {"Python", []checklistItem{
+		{"critical", "pickle, eval, exec or yaml.load on untrusted data"},
+		{"major", "Mutable default arguments and bare except clauses"},
+		{"major", "Files and connections are opened with a context manager"},
+		{"minor", "Type hints on public functions, checked with mypy or pyright"},
+		{"nit", "PEP 8 naming and import order"},
+	}},
+	"javascript": {"JavaScript", []checklistItem{
+		{"critical", "innerHTML, eval or new Function with user-controlled strings"},
+		{"major", "Promises that are neither awaited nor have a rejection handler"},
+		{"major", "== where === is meant, and truthiness checks that reject 0 or \"\""},
+		{"minor", "var instead of const and let"},
+		{"nit", "Consistent module style (ESM or CommonJS, not both)"},
+	}},
+	"typescript": {"TypeScript", []checklistItem{
+		{"critical", "Type assertions (as, !) hiding values that can be null or of another shape at runtime"},
+		{"major", "any in public signatures; prefer unknown and narrowing"},
+		{"major", "Promises that are neither awaited nor have a rejection handler"},
+		{"minor", "Exhaustive switches over unions check never in the default case"},
+		{"nit", "strict mode stays enabled in tsconfig"},
+	}},
+	"java": {"Java", []checklistItem{
+		{"critical", "SQL built by string concatenation instead of PreparedStatement"},
+		{"major", "Streams and connections closed with try-with-resources"},
+		{"major", "equals overridden without hashCode, or mutable objects used as map keys"},
+		{"minor", "Optional used for return values only, never for fields or parameters"},
+		{"nit", "Checked exceptions caught only where they can be handled"},
+	}},
+	"rust": {"Rust", []checklistItem{
+		{"critical", "unsafe blocks document the invariants they rely on"},
+		{"major", "unwrap and expect on values that can fail at runtime"},
+		{"major", "Blocking calls inside async code"},
+		{"minor", "Needless clone where a borrow would do"},
+		{"nit", "cargo clippy and rustfmt are clean"},
+	}},
+	"c": {"C", []checklistItem{
+		{"critical", "Buffer sizes are checked on every copy; no strcpy, sprintf or gets"},
+		{"critical", "Every allocation is freed exactly once and not used after free"},
+		{"major", "Integer overflow in size calculations and signed/unsigned comparisons"},
+		{"minor", "Return values of library calls are checked"},
+		{"nit", "const on pointers that are not written through"},
+	}},
+	"cpp": {"C++", []checklistItem{
+		{"critical", "Raw new and delete instead of smart pointers or containers"},
+		{"critical", "References or iterators that outlive the object they point into"},
+		{"major", "Rule of five: copy and move operations match the destructor"},
+		{"minor", "Pass large objects by const reference"},
+		{"nit", "override on virtual function overrides"},
+	}},
+	"csharp": {"C#", []checklistItem{
+		{"critical", "SQL built by string concatenation instead of parameters"},
+		{"major", "IDisposable objects are disposed with using"},
+		{"major", "async void outside event handlers, and .Result or .Wait() on tasks"},
+		{"minor", "Nullable reference types are enabled and warnings addressed"},
+		{"nit", "LINQ queries are not enumerated more than once"},
+	}},
+	"sql": {"SQL", []checklistItem{
+		{"critical", "UPDATE and DELETE statements have a WHERE clause"},
+		{"major", "Queries filter and join on indexed columns"},
+		{"major", "Migrations can run on a live table without long locks"},
+		{"minor", "SELECT * in application queries"},
+		{"nit", "Consistent keyword case and aliasing"},
+	}},
+	"shell": {"Shell", []checklistItem{
+		{"critical", "Variables are quoted; unquoted expansions split and glob"},
+		{"major", "set -euo pipefail, or explicit checks of exit status"},
+		{"minor", "Temporary files are created with mktemp and removed on exit"},
+		{"nit", "shellcheck is clean"},
+	}},
+}
+
+// checklistAliases maps other spellings of a language to its checklist name.
+var checklistAliases = map[string]string{
+	"golang":     "go",
+	"py":         "python",
+	"js":         "javascript",
+	"ts":         "typescript",
+	"c++":        "cpp",
+	"c#":         "csharp",
+	"bash":       "shell",
+	"sh":         "shell",
+	"postgresql": "sql",
+	"mysql":      "sql",
+}
+
+// checklistName returns the checklist name for a language as written by the
+// user, e.g. "C++" -> "cpp".
+func checklistName(language string) string {
+	name := strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := checklistAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// renderChecklist returns the checklist for language as Markdown: the
+// general items followed by the language's own, grouped by severity.
+// Languages without a checklist of their own get the general items, titled
+// General.
+func renderChecklist(language string) string {
+	title := "General"
+	items := append([]checklistItem(nil), generalChecklist...)
+	if lc, ok := languageChecklists[checklistName(language)]; ok {
+		title = lc.Title
+		items = append(items, lc.Items...)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s review checklist\n", title)
+	for _, severity := range reviewSeverities {
+		fmt.Fprintf(&b, "\n## %s\n\n", severity)
+		for _, item := range items {
+			if item.Severity == severity {
+				fmt.Fprintf(&b, "- [ ] %s\n", item.Text)
+			}
+		}
+	}
+	return b.String()
+}
+
+// checklistLanguages returns the names of the checklists with
+// language-specific items, for completion.
+func checklistLanguages() []string {
+	languages := make([]string, 0, len(languageChecklists))
+	for name := range languageChecklists {
+		languages = append(languages, name)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// registerChecklists adds the review://checklist/{language} resource
+// template and completes its language variable.
+func registerChecklists(s *server.MCPServer, completer *Completer) {
+	template := mcp.NewResourceTemplate(checklistTemplate, "Code Review Checklist",
+		mcp.WithTemplateDescription("Review checklist for a language, grouped by severity. Unknown languages get the general checklist."),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	)
+
+	s.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		language := templateVariable(request, "language")
+		if language == "" {
+			return nil, fmt.Errorf("no language in %s", request.Params.URI)
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "text/markdown",
+				Text:     renderChecklist(language),
+			},
+		}, nil
+	})
+
+	completer.Resource(checklistTemplate, "language", completeFrom(checklistLanguages()...))
+}
+
+// templateVariable returns the value of a URI template variable matched by
+// mcp-go, percent-decoded.
+func templateVariable(request mcp.ReadResourceRequest, name string) string {
+	var value string
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		value = v
+	case []string:
+		if len(v) > 0 {
+			value = v[0]
+		}
+	}
+	if decoded, err := url.PathUnescape(value); err == nil {
+		return decoded
+	}
+	return value
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderChecklist(t *testing.T) {
+	tests := []struct {
+		language string
+		title    string
+		specific string
+	}{
+		{"go", "# Go review checklist", ""},
+		{"Golang", "# Go review checklist", ""},
+		{" TS ", "# TypeScript review checklist", "any in public signatures"},
+		{"javascript", "# JavaScript review checklist", "innerHTML, eval"},
+		{"haskell", "# General review checklist", ""},
+		{"", "# General review checklist", ""},
+	}
+	for _, tt := range tests {
+		text := renderChecklist(tt.language)
+		if !strings.HasPrefix(text, tt.title+"\n") {
+			t.Errorf("renderChecklist(%q) starts %q, want %q", tt.language, strings.SplitN(text, "\n", 2)[0], tt.title)
+		}
+		if tt.specific != "" && !strings.Contains(text, tt.specific) {
+			t.Errorf("renderChecklist(%q) lacks %q", tt.language, tt.specific)
+		}
+		for _, severity := range reviewSeverities {
+			if !strings.Contains(text, "\n## "+severity+"\n") {
+				t.Errorf("renderChecklist(%q) lacks the %s section", tt.language, severity)
+			}
+		}
+	}
+
+	general := renderChecklist("haskell")
+	if strings.Contains(general, "innerHTML") {
+		t.Error("the general checklist includes JavaScript items")
+	}
+}
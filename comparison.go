@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// compareProductsWidgetURI is the URI of the comparison widget.
+const compareProductsWidgetURI = "ui://widget/compare_products.html"
+
+// compareProductsArgs are the arguments of the compare_products tool.
+type compareProductsArgs struct {
+	PriceIDs []string `json:"priceIds" validate:"required,min=2,max=4" description:"Price IDs of the 2 to 4 products to compare, as returned by list_products"`
+}
+
+// featureCell is one product's value for one feature.
+type featureCell struct {
+	PriceID  string `json:"priceId" validate:"required" description:"Product the value belongs to"`
+	Value    string `json:"value" description:"The feature's value, empty if not included"`
+	Included bool   `json:"included" description:"Whether the product has the feature"`
+}
+
+// comparisonRow is one feature across the compared products.
+type comparisonRow struct {
+	Key     string        `json:"key" validate:"required" description:"Feature key"`
+	Label   string        `json:"label" validate:"required" description:"Feature name to display"`
+	Values  []featureCell `json:"values" validate:"required" description:"One value per product, in product order"`
+	Differs bool          `json:"differs" description:"Whether the products differ on this feature"`
+}
+
+// compareProductsOutput is the structuredContent of compare_products.
+type compareProductsOutput struct {
+	Products []Product       `json:"products" validate:"required" description:"The compared products, in the requested order"`
+	Features []comparisonRow `json:"features" validate:"required" description:"The feature matrix, price first"`
+	Cheapest string          `json:"cheapest" validate:"required" description:"Price ID of the cheapest product"`
+}
+
+// compareProducts builds the feature matrix of products, with the price as
+// the first row.
+func compareProducts(products []Product) compareProductsOutput {
+	output := compareProductsOutput{Products: products}
+
+	priceRow := comparisonRow{Key: "price", Label: "Price"}
+	for _, p := range products {
+		priceRow.Values = append(priceRow.Values, featureCell{PriceID: p.PriceID, Value: "$" + p.Price, Included: true})
+	}
+	output.Features = append(output.Features, withDiffers(priceRow))
+
+	for _, feature := range catalogFeatures {
+		row := comparisonRow{Key: feature.Key, Label: feature.Label}
+		for _, p := range products {
+			value, included := p.Features[feature.Key]
+			row.Values = append(row.Values, featureCell{PriceID: p.PriceID, Value: value, Included: included})
+		}
+		output.Features = append(output.Features, withDiffers(row))
+	}
+
+	cheapest, cheapestCents := "", int64(-1)
+	for _, p := range products {
+		if cents, err := parseCents(p.Price); err == nil && (cheapestCents < 0 || cents < cheapestCents) {
+			cheapest, cheapestCents = p.PriceID, cents
+		}
+	}
+	output.Cheapest = cheapest
+	return output
+}
+
+// withDiffers sets row.Differs.
+func withDiffers(row comparisonRow) comparisonRow {
+	first := row.Values[0]
+	for _, cell := range row.Values[1:] {
+		if cell.Value != first.Value || cell.Included != first.Included {
+			row.Differs = true
+		}
+	}
+	return row
+}
+
+// comparisonTable renders output as a Markdown table.
+func comparisonTable(output compareProductsOutput) string {
+	var b strings.Builder
+	b.WriteString("| Feature |")
+	for _, p := range output.Products {
+		fmt.Fprintf(&b, " %s |", p.Name)
+	}
+	b.WriteString("\n|---|")
+	b.WriteString(strings.Repeat("---|", len(output.Products)))
+	for _, row := range output.Features {
+		fmt.Fprintf(&b, "\n| %s |", row.Label)
+		for _, cell := range row.Values {
+			value := cell.Value
+			if !cell.Included {
+				value = "—"
+			}
+			fmt.Fprintf(&b, " %s |", value)
+		}
+	}
+	return b.String()
+}
+
+// registerComparison adds the compare_products tool.
+func registerComparison(s *server.MCPServer) {
+	compareTool := mcp.NewTool("compare_products",
+		mcp.WithDescription("Display a side-by-side feature comparison of catalog products"),
+		withHints(toolHints{Title: "Compare Products", ReadOnly: true, Idempotent: true}),
+		withArguments[compareProductsArgs](),
+		withOutput[compareProductsOutput](),
+	)
+
+	s.AddTool(compareTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args compareProductsArgs) (*mcp.CallToolResult, error) {
+		argErr := &ArgumentError{}
+		var products []Product
+		for i, priceID := range args.PriceIDs {
+			done := traceLookup(ctx, "catalog.product", attribute.String("catalog.price_id", priceID))
+			product, ok := findProduct(priceID)
+			done(ok)
+			switch {
+			case !ok:
+				argErr.add(fmt.Sprintf("priceIds[%d]", i), "is not a product; products are %s", strings.Join(catalogPriceIDs(), ", "))
+			case slices.Contains(args.PriceIDs[:i], priceID):
+				argErr.add(fmt.Sprintf("priceIds[%d]", i), "repeats %s", priceID)
+			default:
+				products = append(products, product.summary())
+			}
+		}
+		if len(argErr.Errors) > 0 {
+			return argErr.Result(), nil
+		}
+
+		output := compareProducts(products)
+		cheapest, _ := findProduct(output.Cheapest)
+		textResponse := "⚖️ **Product Comparison**\n\n" + comparisonTable(output) +
+			fmt.Sprintf("\n\n---\n💡 *%s is the most affordable option at $%s.*", cheapest.Name, cheapest.Price)
+
+		return widgetResult(ctx, compareProductsWidgetURI, compareProductsWidgetFile, textResponse, output), nil
+	}))
+}
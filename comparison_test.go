@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareProducts(t *testing.T) {
+	a := Product{Name: "A", PriceID: "a", Price: "49.99", Features: map[string]string{"users": "5", "storage": "10 GB"}}
+	b := Product{Name: "B", PriceID: "b", Price: "19.99", Features: map[string]string{"users": "5", "storage": "100 GB", "support": "Email"}}
+	c := Product{Name: "C", PriceID: "c", Price: "19.99", Features: map[string]string{"users": "5"}}
+
+	tests := []struct {
+		name     string
+		products []Product
+		cheapest string
+		differs  map[string]bool
+	}{
+		{
+			name:     "two products",
+			products: []Product{a, b},
+			cheapest: "b",
+			differs:  map[string]bool{"price": true, "users": false, "storage": true, "support": true, "api_access": false},
+		},
+		{
+			name:     "tie keeps the first",
+			products: []Product{c, b},
+			cheapest: "c",
+			differs:  map[string]bool{"price": false, "users": false, "storage": true, "support": true},
+		},
+		{
+			name:     "unparsable price",
+			products: []Product{{Name: "X", PriceID: "x", Price: "free"}, a},
+			cheapest: "a",
+			differs:  map[string]bool{"price": true, "users": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := compareProducts(tt.products)
+			if output.Cheapest != tt.cheapest {
+				t.Errorf("Cheapest = %q, want %q", output.Cheapest, tt.cheapest)
+			}
+			if len(output.Features) != len(catalogFeatures)+1 {
+				t.Fatalf("got %d rows, want price and %d features", len(output.Features), len(catalogFeatures))
+			}
+			for _, row := range output.Features {
+				if len(row.Values) != len(tt.products) {
+					t.Errorf("row %s has %d values, want %d", row.Key, len(row.Values), len(tt.products))
+				}
+				if want, ok := tt.differs[row.Key]; ok && row.Differs != want {
+					t.Errorf("row %s Differs = %v, want %v", row.Key, row.Differs, want)
+				}
+			}
+		})
+	}
+}
+
+func TestComparisonTable(t *testing.T) {
+	a := Product{Name: "A", PriceID: "a", Price: "49.99", Features: map[string]string{"storage": "10 GB"}}
+	b := Product{Name: "B", PriceID: "b", Price: "19.99"}
+	table := comparisonTable(compareProducts([]Product{a, b}))
+	for _, want := range []string{"| Feature | A | B |", "| Price | $49.99 | $19.99 |", "| Storage | 10 GB | — |"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table lacks %q:\n%s", want, table)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodComplete is the completion/complete method, which mcp-go parses but
+// does not dispatch.
+const methodComplete mcp.MCPMethod = "completion/complete"
+
+// maxCompletionValues is the most values one response may carry.
+const maxCompletionValues = 100
+
+// CompletionFunc returns the suggestions for an argument. value is what the
+// user has typed so far and arguments holds the other arguments already
+// chosen, so suggestions can depend on them.
+type CompletionFunc func(ctx context.Context, value string, arguments map[string]string) []string
+
+// Completer answers completion/complete for prompt arguments and resource
+// template variables. It runs in front of the MCP server: Middleware over
+// HTTP and HandleMessage for in-process dispatch. Requests go through the
+// same hooks as every other method, so they are audited, counted and traced.
+type Completer struct {
+	hooks *server.Hooks
+
+	mu        sync.RWMutex
+	prompts   map[string]map[string]CompletionFunc // prompt -> argument
+	library   map[string]map[string]CompletionFunc // prompt library, replaced on reload
+	resources map[string]map[string]CompletionFunc // URI template -> variable
+}
+
+// NewCompleter creates an empty completer reporting to hooks.
+func NewCompleter(hooks *server.Hooks) *Completer {
+	return &Completer{
+		hooks:     hooks,
+		prompts:   make(map[string]map[string]CompletionFunc),
+		resources: make(map[string]map[string]CompletionFunc),
+	}
+}
+
+// Prompt registers fn as the completion of argument of the named prompt.
+func (c *Completer) Prompt(name, argument string, fn CompletionFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.prompts[name] == nil {
+		c.prompts[name] = make(map[string]CompletionFunc)
+	}
+	c.prompts[name][argument] = fn
+}
+
+// Resource registers fn as the completion of variable in uriTemplate.
+func (c *Completer) Resource(uriTemplate, variable string, fn CompletionFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.resources[uriTemplate] == nil {
+		c.resources[uriTemplate] = make(map[string]CompletionFunc)
+	}
+	c.resources[uriTemplate][variable] = fn
+}
+
+// ReplacePrompts swaps the prompt library's completions for prompts, keyed
+// by prompt and argument name, with an entry for every library prompt. Completions registered with Prompt take
+// precedence and survive the swap.
+func (c *Completer) ReplacePrompts(prompts map[string]map[string]CompletionFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.library = prompts
+}
+
+// knowsPrompt reports whether name is a registered or library prompt.
+func (c *Completer) knowsPrompt(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, registered := c.prompts[name]
+	_, inLibrary := c.library[name]
+	return registered || inLibrary
+}
+
+// unknownPromptKey marks a completion request for a prompt the completer
+// doesn't know. Its name comes from the client, so metrics and traces
+// report it as "other".
+type unknownPromptKey struct{}
+
+// completeParams is CompleteParams plus the context added in protocol
+// version 2025-06-18.
+type completeParams struct {
+	Ref struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+		URI  string `json:"uri"`
+	} `json:"ref"`
+	Argument struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"argument"`
+	Context struct {
+		Arguments map[string]string `json:"arguments"`
+	} `json:"context"`
+}
+
+// HandleMessage answers message if it is a completion/complete request.
+// ok is false for every other message, which the caller passes on to the
+// MCP server.
+func (c *Completer) HandleMessage(ctx context.Context, message []byte) (response mcp.JSONRPCMessage, ok bool) {
+	request, ok := parseIntercepted(message, methodComplete)
+	if !ok {
+		return nil, false
+	}
+
+	var params completeParams
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return jsonrpcError(mcp.NewRequestId(request.ID), mcp.INVALID_PARAMS, fmt.Sprintf("invalid completion params: %v", err)), true
+	}
+
+	// Hooks see the request in mcp-go's own shape.
+	hookRequest := &mcp.CompleteRequest{Request: mcp.Request{Method: string(methodComplete)}}
+	hookRequest.Params.Argument.Name = params.Argument.Name
+	hookRequest.Params.Argument.Value = params.Argument.Value
+	switch params.Ref.Type {
+	case "ref/prompt":
+		hookRequest.Params.Ref = mcp.PromptReference{Type: params.Ref.Type, Name: params.Ref.Name}
+		if !c.knowsPrompt(params.Ref.Name) {
+			ctx = context.WithValue(ctx, unknownPromptKey{}, true)
+		}
+	case "ref/resource":
+		hookRequest.Params.Ref = mcp.ResourceReference{Type: params.Ref.Type, URI: params.Ref.URI}
+	}
+
+	return respondWithHooks(ctx, c.hooks, request, hookRequest, func() (any, int, error) {
+		return c.complete(ctx, params)
+	}), true
+}
+
+func (c *Completer) complete(ctx context.Context, params completeParams) (*mcp.CompleteResult, int, error) {
+	c.mu.RLock()
+	var fn CompletionFunc
+	switch params.Ref.Type {
+	case "ref/prompt":
+		fn = c.prompts[params.Ref.Name][params.Argument.Name]
+		if fn == nil {
+			fn = c.library[params.Ref.Name][params.Argument.Name]
+		}
+	case "ref/resource":
+		fn = c.resources[params.Ref.URI][params.Argument.Name]
+	default:
+		c.mu.RUnlock()
+		return nil, mcp.INVALID_PARAMS, fmt.Errorf("unknown completion reference type %q", params.Ref.Type)
+	}
+	c.mu.RUnlock()
+
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+	if fn == nil {
+		// Arguments without suggestions, including those of unknown prompts
+		// and templates, complete to nothing.
+		return result, 0, nil
+	}
+
+	values := fn(ctx, params.Argument.Value, params.Context.Arguments)
+	result.Completion.Total = len(values)
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+		result.Completion.HasMore = true
+	}
+	result.Completion.Values = append(result.Completion.Values, values...)
+	return result, 0, nil
+}
+
+// Middleware answers completion requests posted to /mcp and advertises the
+// completions capability in initialize responses.
+func (c *Completer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := readRequestBody(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if response, ok := c.HandleMessage(r.Context(), body); ok {
+			writeJSONRPC(w, response)
+			return
+		}
+
+		var message struct {
+			Method mcp.MCPMethod `json:"method"`
+		}
+		if json.Unmarshal(body, &message) != nil || message.Method != mcp.MethodInitialize {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+		out := buf.body.Bytes()
+		if buf.status == http.StatusOK && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			out = withCompletionsCapability(out)
+		}
+		w.WriteHeader(buf.status)
+		w.Write(out)
+	})
+}
+
+// withCompletionsCapability adds capabilities.completions to an initialize
+// response. Anything it can't parse is returned unchanged.
+func withCompletionsCapability(response []byte) []byte {
+	var message map[string]any
+	if json.Unmarshal(response, &message) != nil {
+		return response
+	}
+	result, _ := message["result"].(map[string]any)
+	capabilities, _ := result["capabilities"].(map[string]any)
+	if capabilities == nil {
+		return response
+	}
+	capabilities["completions"] = map[string]any{}
+	out, err := json.Marshal(message)
+	if err != nil {
+		return response
+	}
+	return append(out, '\n')
+}
+
+// bufferedResponse holds a response back so it can be rewritten.
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+// Flush is a no-op: the response is sent once it is complete.
+func (b *bufferedResponse) Flush() {}
+
+// completeFrom returns a CompletionFunc suggesting the values that start
+// with what the user typed, ignoring case.
+func completeFrom(values ...string) CompletionFunc {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return func(ctx context.Context, value string, arguments map[string]string) []string {
+		prefix := strings.ToLower(value)
+		var matches []string
+		for _, v := range sorted {
+			if strings.HasPrefix(strings.ToLower(v), prefix) {
+				matches = append(matches, v)
+			}
+		}
+		return matches
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config mirrors the layout of config.example.json. Any field missing from
+// the file keeps the value from defaultConfig.
+type Config struct {
+	Server   ServerConfig   `json:"server"`
+	Timeouts TimeoutsConfig `json:"timeouts"`
+	Audit    AuditConfig    `json:"audit"`
+	Tracing  TracingConfig  `json:"tracing"`
+	Shutdown ShutdownConfig `json:"shutdown"`
+	TLS      TLSConfig      `json:"tls"`
+	CORS     CORSConfig     `json:"cors"`
+	Prompts  PromptsConfig  `json:"prompts"`
+	Widgets  WidgetsConfig  `json:"widgets"`
+}
+
+// ServerConfig controls where the HTTP listener binds. The server name and
+// version stay compile-time constants so clients always see the same identity.
+type ServerConfig struct {
+	Port string `json:"port"`
+	Host string `json:"host"`
+	// DevMode binds to localhost unless Host is set and admits localhost
+	// browser origins on /mcp.
+	DevMode bool `json:"dev_mode"`
+	// Stateful keeps MCP sessions across requests. It is required for
+	// server-to-client requests such as elicitation, which also need the
+	// client to hold a GET stream open on /mcp.
+	Stateful bool `json:"stateful"`
+}
+
+// TimeoutsConfig holds the HTTP server timeouts in seconds.
+type TimeoutsConfig struct {
+	Read  int `json:"read"`
+	Write int `json:"write"`
+	Idle  int `json:"idle"`
+	// Elicitation is how long a tool call waits for the user to answer.
+	Elicitation int `json:"elicitation"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port: serverPort,
+		},
+		Timeouts: TimeoutsConfig{
+			Read:        15,
+			Write:       15,
+			Idle:        60,
+			Elicitation: 120,
+		},
+		Audit: AuditConfig{
+			Path:       "logs/audit.jsonl",
+			MaxSizeMB:  10,
+			MaxBackups: 5,
+			RedactKeys: []string{"password", "token", "secret", "api_key", "authorization"},
+		},
+		Tracing: TracingConfig{
+			Exporter:    "otlp",
+			SampleRatio: 1,
+		},
+		Shutdown: ShutdownConfig{
+			GracePeriod:  20,
+			HammerPeriod: 5,
+		},
+		TLS: TLSConfig{
+			MinVersion:     "1.2",
+			ReloadInterval: 30,
+		},
+		CORS: CORSConfig{
+			MaxAge: 600,
+		},
+		Prompts: PromptsConfig{
+			Dir:            "prompts",
+			ReloadInterval: 5,
+		},
+		Widgets: WidgetsConfig{
+			WatchInterval: 5,
+		},
+	}
+}
+
+// configPath returns the config file location, overridable with MCP_CONFIG.
+func configPath() string {
+	if path := os.Getenv("MCP_CONFIG"); path != "" {
+		return path
+	}
+	return "config.json"
+}
+
+// loadConfig reads the JSON config at path on top of the defaults. A missing
+// file is not an error so the server still starts with zero setup.
+func loadConfig(path string) (*Config, error) {
+	cfg := defaultConfig()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	if err := cfg.CORS.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) addr() string {
+	host := c.Server.Host
+	if host == "" && c.Server.DevMode {
+		host = "127.0.0.1"
+	}
+	return host + ":" + c.Server.Port
+}
+
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
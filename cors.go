@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CORSConfig controls which browser origins may call /mcp. Requests without
+// an Origin header (server-side MCP clients) are always allowed.
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins ("https://app.example.com"),
+	// subdomain wildcards ("https://*.example.com") or "*".
+	AllowedOrigins   []string `json:"allowed_origins"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	// MaxAge is how long, in seconds, browsers may cache a preflight.
+	MaxAge int `json:"max_age"`
+}
+
+// validate rejects allowing credentials for every origin, which would let
+// any website make credentialed calls to /mcp.
+func (c CORSConfig) validate() error {
+	if !c.AllowCredentials {
+		return nil
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return fmt.Errorf("cors.allow_credentials cannot be combined with \"*\" in cors.allowed_origins; list the origins instead")
+		}
+	}
+	return nil
+}
+
+const (
+	corsAllowMethods  = "GET, POST, DELETE, OPTIONS"
+	corsAllowHeaders  = "Content-Type, Accept, Authorization, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID, traceparent, tracestate"
+	corsExposeHeaders = "Mcp-Session-Id"
+)
+
+// OriginGuard validates the Origin header of /mcp requests to prevent DNS
+// rebinding and answers CORS preflights for the allowed origins.
+type OriginGuard struct {
+	cfg CORSConfig
+	// allowLocalhost admits any localhost origin; it is set in dev mode.
+	allowLocalhost bool
+}
+
+// NewOriginGuard creates a guard for cfg. In dev mode browser tools served
+// from localhost are allowed without listing them.
+func NewOriginGuard(cfg CORSConfig, devMode bool) *OriginGuard {
+	return &OriginGuard{cfg: cfg, allowLocalhost: devMode}
+}
+
+// Allowed reports whether a request carrying origin may be served.
+func (g *OriginGuard) Allowed(origin string) bool {
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+	if g.allowLocalhost && isLocalhost(u.Hostname()) {
+		return true
+	}
+
+	for _, allowed := range g.cfg.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		scheme, pattern, ok := strings.Cut(allowed, "://*.")
+		if ok && strings.EqualFold(u.Scheme, scheme) &&
+			strings.HasSuffix(strings.ToLower(u.Host), "."+strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLocalhost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Middleware rejects disallowed origins with 403, answers preflights and
+// adds CORS response headers for allowed browser origins.
+func (g *OriginGuard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+
+		if !g.Allowed(origin) {
+			http.Error(w, "Origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		if origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			if g.cfg.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if r.Method == http.MethodOptions {
+			if origin != "" && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+				if g.cfg.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(g.cfg.MaxAge))
+				}
+			}
+			w.Header().Set("Allow", corsAllowMethods)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
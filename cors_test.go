@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginGuardAllowed(t *testing.T) {
+	guard := NewOriginGuard(CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"},
+	}, false)
+	devGuard := NewOriginGuard(CORSConfig{}, true)
+
+	tests := []struct {
+		name   string
+		guard  *OriginGuard
+		origin string
+		want   bool
+	}{
+		{"no origin", guard, "", true},
+		{"exact", guard, "https://app.example.com", true},
+		{"exact ignores case", guard, "https://APP.example.com", true},
+		{"other host", guard, "https://evil.example.com", false},
+		{"other scheme", guard, "http://app.example.com", false},
+		{"subdomain wildcard", guard, "https://a.b.example.org", true},
+		{"wildcard needs a subdomain", guard, "https://example.org", false},
+		{"wildcard suffix trick", guard, "https://evilexample.org", false},
+		{"wildcard scheme", guard, "http://a.example.org", false},
+		{"malformed", guard, "not a url", false},
+		{"null origin", guard, "null", false},
+		{"localhost outside dev mode", guard, "http://localhost:3000", false},
+		{"localhost in dev mode", devGuard, "http://localhost:3000", true},
+		{"loopback in dev mode", devGuard, "http://127.0.0.1:5173", true},
+		{"remote in dev mode", devGuard, "https://app.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.guard.Allowed(tt.origin); got != tt.want {
+				t.Errorf("Allowed(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOriginGuardMiddleware(t *testing.T) {
+	guard := NewOriginGuard(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, MaxAge: 600}, false)
+	handler := guard.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		preflight   bool
+		wantStatus  int
+		wantAllowed string
+		wantMaxAge  string
+	}{
+		{"server-side client", http.MethodPost, "", false, http.StatusOK, "", ""},
+		{"allowed origin", http.MethodPost, "https://app.example.com", false, http.StatusOK, "https://app.example.com", ""},
+		{"disallowed origin", http.MethodPost, "https://evil.example.com", false, http.StatusForbidden, "", ""},
+		{"preflight", http.MethodOptions, "https://app.example.com", true, http.StatusNoContent, "https://app.example.com", "600"},
+		{"disallowed preflight", http.MethodOptions, "https://evil.example.com", true, http.StatusForbidden, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/mcp", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowed {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowed)
+			}
+			if got := w.Header().Get("Access-Control-Max-Age"); got != tt.wantMaxAge {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, tt.wantMaxAge)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestCORSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     CORSConfig
+		wantErr bool
+	}{
+		{"defaults", CORSConfig{}, false},
+		{"wildcard", CORSConfig{AllowedOrigins: []string{"*"}}, false},
+		{"credentials with origins", CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}, false},
+		{"credentials with wildcard", CORSConfig{AllowedOrigins: []string{"https://app.example.com", "*"}, AllowCredentials: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
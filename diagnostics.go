@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// diagnosticsURI is the URI of the resource the diagnostics tool embeds.
+const diagnosticsURI = "diagnostics://request"
+
+// diagnosticsArgs are the arguments of the diagnostics tool. Any other
+// arguments are accepted and reflected back as well.
+type diagnosticsArgs struct {
+	Message string `json:"message" validate:"max=10000" description:"Text to echo back"`
+	Content string `json:"content" validate:"enum=text|image|audio|resource|resource_link|all" description:"Content type to return after the text summary (default text only)"`
+	Error   string `json:"error" validate:"enum=tool_error|argument_error|internal_error" description:"Fail deliberately with this error shape: an isError result, an invalid-arguments result with structuredContent, or a JSON-RPC error"`
+}
+
+// diagnosticsOutput is the structuredContent of diagnostics.
+type diagnosticsOutput struct {
+	Message         string              `json:"message,omitempty" description:"The message argument"`
+	Arguments       map[string]any      `json:"arguments" validate:"required" description:"Every argument exactly as the server parsed it"`
+	Meta            any                 `json:"meta,omitempty" description:"The _meta of the request, such as its progressToken"`
+	Session         *diagnosticsSession `json:"session,omitempty" description:"The MCP session; absent in stateless mode"`
+	ProtocolVersion string              `json:"protocolVersion,omitempty" description:"The Mcp-Protocol-Version the client sent"`
+	Headers         map[string]string   `json:"headers" validate:"required" description:"HTTP headers of interest; credentials are redacted"`
+	ServerTime      string              `json:"serverTime" validate:"required" description:"When the server handled the call, as an RFC 3339 timestamp"`
+}
+
+// diagnosticsSession describes the session a call arrived on.
+type diagnosticsSession struct {
+	ID            string `json:"id" description:"Mcp-Session-Id"`
+	Initialized   bool   `json:"initialized" description:"Whether the client sent notifications/initialized"`
+	ClientName    string `json:"clientName,omitempty" description:"clientInfo.name from initialize"`
+	ClientVersion string `json:"clientVersion,omitempty" description:"clientInfo.version from initialize"`
+	Capabilities  any    `json:"capabilities,omitempty" description:"Client capabilities from initialize"`
+}
+
+// diagnosticHeaders are the request headers reflected back. Credentials
+// are reported as present but never echoed.
+var diagnosticHeaders = []string{
+	"Accept",
+	"Content-Type",
+	"Mcp-Protocol-Version",
+	"Mcp-Session-Id",
+	"Origin",
+	"Traceparent",
+	"User-Agent",
+	"X-Forwarded-For",
+	"X-Forwarded-Proto",
+	"X-Request-Id",
+}
+
+var redactedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+func diagnosticHeaderValues(header http.Header) map[string]string {
+	values := map[string]string{}
+	for _, name := range diagnosticHeaders {
+		if value := header.Values(name); len(value) > 0 {
+			values[name] = strings.Join(value, ", ")
+		}
+	}
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			values[name] = "[redacted]"
+		}
+	}
+	return values
+}
+
+// sessionDiagnostics describes the session of ctx, or nil without one.
+func sessionDiagnostics(ctx context.Context) *diagnosticsSession {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" {
+		return nil
+	}
+	info := &diagnosticsSession{ID: session.SessionID(), Initialized: session.Initialized()}
+	if withInfo, ok := session.(server.SessionWithClientInfo); ok {
+		client := withInfo.GetClientInfo()
+		info.ClientName, info.ClientVersion = client.Name, client.Version
+		info.Capabilities = withInfo.GetClientCapabilities()
+	}
+	return info
+}
+
+// diagnosticImage is a 32x32 PNG gradient, base64-encoded.
+var diagnosticImage = func() string {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 8), G: uint8(y * 8), B: 160, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}()
+
+// diagnosticAudio is a quarter second 440 Hz tone as an 8 kHz, 8-bit mono
+// WAV file, base64-encoded.
+var diagnosticAudio = func() string {
+	const rate, samples = 8000, 2000
+	var buf bytes.Buffer
+	write := func(v any) { binary.Write(&buf, binary.LittleEndian, v) }
+	buf.WriteString("RIFF")
+	write(uint32(36 + samples))
+	buf.WriteString("WAVEfmt ")
+	write(uint32(16))   // fmt chunk size
+	write(uint16(1))    // PCM
+	write(uint16(1))    // mono
+	write(uint32(rate)) // sample rate
+	write(uint32(rate)) // byte rate
+	write(uint16(1))    // block align
+	write(uint16(8))    // bits per sample
+	buf.WriteString("data")
+	write(uint32(samples))
+	for i := 0; i < samples; i++ {
+		buf.WriteByte(uint8(128 + 100*math.Sin(2*math.Pi*440*float64(i)/rate)))
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}()
+
+// diagnosticContent returns the content blocks of kind, one of the
+// content argument values.
+func diagnosticContent(kind string, output diagnosticsOutput) []mcp.Content {
+	var content []mcp.Content
+	if kind == "image" || kind == "all" {
+		content = append(content, mcp.NewImageContent(diagnosticImage, "image/png"))
+	}
+	if kind == "audio" || kind == "all" {
+		content = append(content, mcp.NewAudioContent(diagnosticAudio, "audio/wav"))
+	}
+	if kind == "resource" || kind == "all" {
+		data, _ := json.MarshalIndent(output, "", "  ")
+		content = append(content, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      diagnosticsURI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		}))
+	}
+	if kind == "resource_link" || kind == "all" {
+		content = append(content, mcp.NewResourceLink(serverInfoURI, "Server Information",
+			"Build, uptime and capabilities of this server", "application/json"))
+	}
+	return content
+}
+
+// diagnosticsText summarizes output for the text content block.
+func diagnosticsText(output diagnosticsOutput) string {
+	var b strings.Builder
+	if output.Message != "" {
+		fmt.Fprintf(&b, "Echo: %s\n\n", output.Message)
+	}
+	arguments, _ := json.Marshal(output.Arguments)
+	fmt.Fprintf(&b, "Arguments: %s\n", arguments)
+	if output.Session != nil {
+		fmt.Fprintf(&b, "Session: %s (initialized: %t)\n", output.Session.ID, output.Session.Initialized)
+		if output.Session.ClientName != "" {
+			fmt.Fprintf(&b, "Client: %s %s\n", output.Session.ClientName, output.Session.ClientVersion)
+		}
+	} else {
+		b.WriteString("Session: none (stateless)\n")
+	}
+	if output.ProtocolVersion != "" {
+		fmt.Fprintf(&b, "Protocol version: %s\n", output.ProtocolVersion)
+	}
+	names := make([]string, 0, len(output.Headers))
+	for name := range output.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s: %s\n", name, output.Headers[name])
+	}
+	fmt.Fprintf(&b, "Server time: %s", output.ServerTime)
+	return b.String()
+}
+
+// registerDiagnostics adds the diagnostics tool.
+func registerDiagnostics(s *server.MCPServer) {
+	diagnosticsTool := mcp.NewTool("diagnostics",
+		mcp.WithDescription("Reflects the call back for debugging MCP clients: the arguments as parsed, session, client and protocol details and request headers. Can return every content type and fail with every error shape so you can check how a host renders them."),
+		withHints(toolHints{Title: "Protocol Diagnostics", ReadOnly: true, Idempotent: true}),
+		withArguments[diagnosticsArgs](),
+		withOutput[diagnosticsOutput](),
+	)
+
+	s.AddTool(diagnosticsTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args diagnosticsArgs) (*mcp.CallToolResult, error) {
+		switch args.Error {
+		case "tool_error":
+			return mcp.NewToolResultError("Diagnostics: deliberate tool error"), nil
+		case "argument_error":
+			argErr := &ArgumentError{}
+			argErr.add("error", "deliberate invalid argument")
+			return argErr.Result(), nil
+		case "internal_error":
+			return nil, errors.New("diagnostics: deliberate internal error")
+		}
+
+		arguments := request.GetArguments()
+		if arguments == nil {
+			arguments = map[string]any{}
+		}
+		output := diagnosticsOutput{
+			Message:         args.Message,
+			Arguments:       arguments,
+			Session:         sessionDiagnostics(ctx),
+			ProtocolVersion: request.Header.Get(server.HeaderKeyProtocolVersion),
+			Headers:         diagnosticHeaderValues(request.Header),
+			ServerTime:      time.Now().UTC().Format(time.RFC3339Nano),
+		}
+		if request.Params.Meta != nil {
+			output.Meta = request.Params.Meta
+		}
+
+		content := []mcp.Content{mcp.NewTextContent(diagnosticsText(output))}
+		return &mcp.CallToolResult{
+			Content:           append(content, diagnosticContent(args.Content, output)...),
+			StructuredContent: output,
+		}, nil
+	}))
+}
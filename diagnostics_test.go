@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// callTool calls a tool through an MCP server set up by register and
+// returns the JSON-RPC response.
+func callTool(t *testing.T, register func(*server.MCPServer), name string, arguments map[string]any) map[string]any {
+	t.Helper()
+	s := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(true))
+	register(s)
+	request, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": arguments},
+	})
+	data, _ := json.Marshal(s.HandleMessage(context.Background(), request))
+	var response map[string]any
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+func callDiagnostics(t *testing.T, arguments map[string]any) map[string]any {
+	t.Helper()
+	return callTool(t, registerDiagnostics, "diagnostics", arguments)
+}
+
+func TestDiagnosticsArguments(t *testing.T) {
+	arguments := map[string]any{
+		"message": "hi",
+		"count":   float64(3),
+		"nested":  map[string]any{"list": []any{"a", true, nil}},
+		"empty":   "",
+	}
+	result, _ := callDiagnostics(t, arguments)["result"].(map[string]any)
+	structured, _ := result["structuredContent"].(map[string]any)
+	if got := structured["arguments"]; !reflect.DeepEqual(got, arguments) {
+		t.Errorf("arguments = %v, want %v", got, arguments)
+	}
+	if structured["message"] != "hi" || result["isError"] == true {
+		t.Errorf("result = %v", result)
+	}
+}
+
+func TestDiagnosticsContent(t *testing.T) {
+	tests := []struct {
+		content string
+		types   []string
+	}{
+		{"", []string{"text"}},
+		{"text", []string{"text"}},
+		{"image", []string{"text", "image"}},
+		{"audio", []string{"text", "audio"}},
+		{"resource", []string{"text", "resource"}},
+		{"resource_link", []string{"text", "resource_link"}},
+		{"all", []string{"text", "image", "audio", "resource", "resource_link"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			result, _ := callDiagnostics(t, map[string]any{"content": tt.content})["result"].(map[string]any)
+			blocks, _ := result["content"].([]any)
+			var types []string
+			for _, block := range blocks {
+				types = append(types, block.(map[string]any)["type"].(string))
+			}
+			if !reflect.DeepEqual(types, tt.types) {
+				t.Errorf("content types = %v, want %v", types, tt.types)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsErrors(t *testing.T) {
+	tests := []struct {
+		error   string
+		isError bool
+		fields  []any
+		code    float64
+	}{
+		{"tool_error", true, nil, 0},
+		{"argument_error", true, []any{map[string]any{"field": "error", "message": "deliberate invalid argument"}}, 0},
+		{"internal_error", false, nil, mcp.INTERNAL_ERROR},
+	}
+	for _, tt := range tests {
+		t.Run(tt.error, func(t *testing.T) {
+			response := callDiagnostics(t, map[string]any{"error": tt.error})
+			if tt.code != 0 {
+				rpcErr, _ := response["error"].(map[string]any)
+				if rpcErr["code"] != tt.code {
+					t.Errorf("response = %v, want JSON-RPC error %v", response, tt.code)
+				}
+				return
+			}
+			result, _ := response["result"].(map[string]any)
+			if result["isError"] != tt.isError {
+				t.Errorf("isError = %v, want %v", result["isError"], tt.isError)
+			}
+			structured, _ := result["structuredContent"].(map[string]any)
+			if errors, _ := structured["errors"].([]any); !reflect.DeepEqual(errors, tt.fields) {
+				t.Errorf("structuredContent errors = %v, want %v", errors, tt.fields)
+			}
+		})
+	}
+}
+
+func TestDiagnosticHeaderValues(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+	header.Set("Cookie", "session=secret")
+	header.Set("User-Agent", "test/1.0")
+	header.Add("X-Forwarded-For", "10.0.0.1")
+	header.Add("X-Forwarded-For", "10.0.0.2")
+	header.Set("X-Unlisted", "ignored")
+
+	want := map[string]string{
+		"Authorization":   "[redacted]",
+		"Cookie":          "[redacted]",
+		"User-Agent":      "test/1.0",
+		"X-Forwarded-For": "10.0.0.1, 10.0.0.2",
+	}
+	if got := diagnosticHeaderValues(header); !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnosticHeaderValues = %v, want %v", got, want)
+	}
+}
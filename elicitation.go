@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// errElicitationUnsupported is returned by elicit when the calling client
+// cannot answer elicitation requests: it did not declare the capability or
+// the server runs stateless, so there is no session to send the request on.
+var errElicitationUnsupported = errors.New("client does not support elicitation")
+
+// errElicitationDeclined is returned when the user declines or cancels.
+var errElicitationDeclined = errors.New("the user declined to answer")
+
+// elicitationTimeout bounds how long a tool call waits for the user. It is
+// set from timeouts.elicitation in newMCPServer.
+var elicitationTimeout = 2 * time.Minute
+
+// withoutWriteDeadline clears the server's write deadline for next, whose
+// responses may outlive timeouts.write: tool calls waiting up to
+// timeouts.elicitation for the user and notification streams.
+func withoutWriteDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			log.Printf("Failed to clear write deadline: %v", err)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// supportsElicitation reports whether the client behind ctx declared the
+// elicitation capability on a session the server can write to.
+func supportsElicitation(ctx context.Context) bool {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return false
+	}
+	if _, ok := session.(server.SessionWithElicitation); !ok {
+		return false
+	}
+	return session.GetClientCapabilities().Elicitation != nil
+}
+
+// elicit asks the user for input matching schema and waits for the answer.
+// The client shows message alongside a form built from schema.
+func elicit(ctx context.Context, message string, schema map[string]any) (*mcp.ElicitationResult, error) {
+	if !supportsElicitation(ctx) {
+		return nil, errElicitationUnsupported
+	}
+	s := server.ServerFromContext(ctx)
+	if s == nil {
+		return nil, errElicitationUnsupported
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, elicitationTimeout)
+	defer cancel()
+	result, err := s.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message:         message,
+			RequestedSchema: schema,
+		},
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, errors.New("timed out waiting for the user")
+	}
+	return result, err
+}
+
+// elicitAccepted reports whether the user submitted the form.
+func elicitAccepted(result *mcp.ElicitationResult) bool {
+	return result != nil && result.Action == mcp.ElicitationResponseActionAccept
+}
+
+// elicitBool returns the boolean field name of an accepted answer.
+func elicitBool(result *mcp.ElicitationResult, name string) bool {
+	content, _ := result.Content.(map[string]any)
+	value, _ := content[name].(bool)
+	return value
+}
+
+// elicitValues asks the user for the properties of schema and returns the
+// submitted values. A declined or cancelled request yields
+// errElicitationDeclined.
+func elicitValues(ctx context.Context, message string, schema map[string]any) (map[string]any, error) {
+	result, err := elicit(ctx, message, schema)
+	if err != nil {
+		return nil, err
+	}
+	if !elicitAccepted(result) {
+		return nil, errElicitationDeclined
+	}
+	content, _ := result.Content.(map[string]any)
+	return content, nil
+}
+
+// elicitMissingArguments asks the user for the required arguments missing
+// from request and binds the completed arguments. It returns a result when
+// the user declines, and argErr unchanged when the arguments can't be asked
+// for, so the caller reports them as before.
+func elicitMissingArguments[T any](ctx context.Context, request mcp.CallToolRequest, argErr *ArgumentError) (T, *mcp.CallToolResult, error) {
+	var zero T
+	missing := argErr.missing()
+	properties := argumentsSchema(reflect.TypeOf((*T)(nil)).Elem()).Properties
+
+	// Elicitation forms only support flat primitive fields.
+	requested := make(map[string]any, len(missing))
+	for _, name := range missing {
+		prop, _ := properties[name].(map[string]any)
+		switch prop["type"] {
+		case "string", "number", "integer", "boolean":
+			requested[name] = prop
+		default:
+			return zero, nil, argErr
+		}
+	}
+
+	tool := request.Params.Name
+	names := strings.Join(missing, ", ")
+	content, err := elicitValues(ctx, fmt.Sprintf("%s needs %s to continue.", tool, names), map[string]any{
+		"type":       "object",
+		"properties": requested,
+		"required":   missing,
+	})
+	if errors.Is(err, errElicitationDeclined) {
+		return zero, mcp.NewToolResultError(fmt.Sprintf("%s was not run: the user did not provide %s", tool, names)), nil
+	}
+	if err != nil {
+		log.Printf("Asking for missing arguments of %s failed: %v", tool, err)
+		return zero, nil, argErr
+	}
+
+	merged := make(map[string]any)
+	if raw, ok := request.Params.Arguments.(map[string]any); ok {
+		for k, v := range raw {
+			merged[k] = v
+		}
+	}
+	for name := range requested {
+		if v, ok := content[name]; ok {
+			merged[name] = v
+		}
+	}
+	args, err := bindValues[T](merged)
+	return args, nil, err
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HealthCheck probes one dependency and returns an error when it is not
+// usable. Checks must honour ctx cancellation.
+type HealthCheck func(ctx context.Context) error
+
+// HealthChecker is the registry of readiness checks. Subsystems such as the
+// widget files, catalog store, session store or payment provider register a
+// check when they are set up; /readyz runs them all.
+type HealthChecker struct {
+	timeout time.Duration
+	started time.Time
+
+	mu     sync.RWMutex
+	checks map[string]HealthCheck
+}
+
+// NewHealthChecker creates an empty registry. Each check gets timeout to
+// complete before it is reported as failed.
+func NewHealthChecker(timeout time.Duration) *HealthChecker {
+	return &HealthChecker{
+		timeout: timeout,
+		started: time.Now(),
+		checks:  make(map[string]HealthCheck),
+	}
+}
+
+// Register adds (or replaces) the check called name.
+func (h *HealthChecker) Register(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// Unregister removes the check called name.
+func (h *HealthChecker) Unregister(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.checks, name)
+}
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+)
+
+type healthCheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type healthReport struct {
+	Status  string                       `json:"status"`
+	Uptime  string                       `json:"uptime"`
+	Checks  map[string]healthCheckResult `json:"checks,omitempty"`
+	Failing []string                     `json:"failing,omitempty"`
+}
+
+// Check runs every registered check concurrently and collects the results.
+func (h *HealthChecker) Check(ctx context.Context) healthReport {
+	h.mu.RLock()
+	checks := make(map[string]HealthCheck, len(h.checks))
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+	h.mu.RUnlock()
+
+	report := healthReport{
+		Status: healthStatusOK,
+		Uptime: time.Since(h.started).Round(time.Second).String(),
+		Checks: make(map[string]healthCheckResult, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+			result := h.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != healthStatusOK {
+				report.Status = healthStatusFail
+				report.Failing = append(report.Failing, name)
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	sort.Strings(report.Failing)
+	return report
+}
+
+func (h *HealthChecker) run(ctx context.Context, check HealthCheck) healthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errc <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		errc <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", h.timeout)
+	}
+
+	result := healthCheckResult{
+		Status:    healthStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = healthStatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LiveHandler reports that the process is up and serving HTTP. It never runs
+// dependency checks so a slow backend cannot get the process restarted.
+func (h *HealthChecker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, http.StatusOK, healthReport{
+			Status: healthStatusOK,
+			Uptime: time.Since(h.started).Round(time.Second).String(),
+		})
+	})
+}
+
+// ReadyHandler runs the registered checks and answers 503 if any fail.
+func (h *HealthChecker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := h.Check(r.Context())
+		status := http.StatusOK
+		if report.Status != healthStatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		writeHealthReport(w, status, report)
+	})
+}
+
+func writeHealthReport(w http.ResponseWriter, status int, report healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// widgetFilesCheck verifies that every widget HTML file is present and
+// readable.
+func widgetFilesCheck(paths ...string) HealthCheck {
+	return func(ctx context.Context) error {
+		for _, path := range paths {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return fmt.Errorf("widget %s: %v", path, err)
+			}
+			f.Close()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registry is everything the MCP server has registered right now.
+type registry struct {
+	Tools             []mcp.Tool
+	Resources         []mcp.Resource
+	ResourceTemplates []mcp.ResourceTemplate
+	Prompts           []mcp.Prompt
+}
+
+// registry lists the registered tools, resources, templates and prompts.
+// The lists come from the server itself, so runtime changes show up.
+func (a *mcpApp) registry(ctx context.Context) (*registry, error) {
+	reg := &registry{}
+	for _, tool := range a.server.ListTools() {
+		reg.Tools = append(reg.Tools, tool.Tool)
+	}
+	sort.Slice(reg.Tools, func(i, j int) bool { return reg.Tools[i].Name < reg.Tools[j].Name })
+
+	result, err := dispatchInternal(ctx, a.server, mcp.MethodResourcesList, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %v", err)
+	}
+	if resources, ok := result.(mcp.ListResourcesResult); ok {
+		reg.Resources = resources.Resources
+	}
+	result, err = dispatchInternal(ctx, a.server, mcp.MethodResourcesTemplatesList, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource templates: %v", err)
+	}
+	if templates, ok := result.(mcp.ListResourceTemplatesResult); ok {
+		reg.ResourceTemplates = templates.ResourceTemplates
+	}
+	result, err = dispatchInternal(ctx, a.server, mcp.MethodPromptsList, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompts: %v", err)
+	}
+	if prompts, ok := result.(mcp.ListPromptsResult); ok {
+		reg.Prompts = prompts.Prompts
+	}
+	return reg, nil
+}
+
+// infoEndpoints describes the HTTP routes next to /mcp.
+var infoEndpoints = map[string]string{
+	"mcp":     "/mcp (POST for MCP protocol)",
+	"health":  "/health (GET for health check)",
+	"livez":   "/livez (GET for liveness probe)",
+	"readyz":  "/readyz (GET for readiness probe with dependency checks)",
+	"metrics": "/metrics (GET for Prometheus metrics)",
+	"schemas": "/schemas (GET for tool input/output schemas)",
+}
+
+// infoPage is the GET /mcp page, as JSON or rendered to HTML.
+type infoPage struct {
+	Name              string                 `json:"name"`
+	Version           string                 `json:"version"`
+	Description       string                 `json:"description"`
+	Endpoints         map[string]string      `json:"endpoints"`
+	Tools             []infoTool             `json:"tools"`
+	Resources         []mcp.Resource         `json:"resources"`
+	ResourceTemplates []mcp.ResourceTemplate `json:"resourceTemplates"`
+	Prompts           []infoPrompt           `json:"prompts"`
+	Usage             string                 `json:"usage"`
+}
+
+type infoTool struct {
+	toolSchemas
+	Title       string             `json:"title,omitempty"`
+	Annotations mcp.ToolAnnotation `json:"annotations"`
+	Example     map[string]any     `json:"example"` // a tools/call request
+}
+
+type infoPrompt struct {
+	mcp.Prompt
+	Example map[string]any `json:"example"` // a prompts/get request
+}
+
+// newInfoPage builds the info page from reg.
+func newInfoPage(reg *registry) *infoPage {
+	page := &infoPage{
+		Name:              serverName,
+		Version:           serverVersion,
+		Description:       "MCP Server with Product Widget Tool",
+		Endpoints:         infoEndpoints,
+		Tools:             []infoTool{},
+		Resources:         reg.Resources,
+		ResourceTemplates: reg.ResourceTemplates,
+		Prompts:           []infoPrompt{},
+		Usage:             "Send POST requests with JSON-RPC 2.0 format to /mcp endpoint",
+	}
+	for _, tool := range reg.Tools {
+		page.Tools = append(page.Tools, infoTool{
+			toolSchemas: newToolSchemas(tool),
+			Title:       tool.Annotations.Title,
+			Annotations: tool.Annotations,
+			Example: exampleRequest(mcp.MethodToolsCall, map[string]any{
+				"name":      tool.Name,
+				"arguments": exampleArguments(tool.InputSchema),
+			}),
+		})
+	}
+	for _, prompt := range reg.Prompts {
+		arguments := map[string]string{}
+		for _, arg := range prompt.Arguments {
+			if arg.Required {
+				arguments[arg.Name] = "example"
+			}
+		}
+		page.Prompts = append(page.Prompts, infoPrompt{
+			Prompt: prompt,
+			Example: exampleRequest(mcp.MethodPromptsGet, map[string]any{
+				"name":      prompt.Name,
+				"arguments": arguments,
+			}),
+		})
+	}
+	return page
+}
+
+func exampleRequest(method mcp.MCPMethod, params map[string]any) map[string]any {
+	return map[string]any{"jsonrpc": mcp.JSONRPC_VERSION, "id": 1, "method": method, "params": params}
+}
+
+// exampleArguments returns a placeholder for every required argument in
+// schema, honouring enums and lower bounds so the example validates.
+func exampleArguments(schema mcp.ToolInputSchema) map[string]any {
+	arguments := map[string]any{}
+	for _, name := range schema.Required {
+		prop, _ := schema.Properties[name].(map[string]any)
+		arguments[name] = exampleValue(prop)
+	}
+	return arguments
+}
+
+func exampleValue(prop map[string]any) any {
+	if enum, ok := prop["enum"].([]string); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	switch prop["type"] {
+	case "number", "integer":
+		if min, ok := prop["minimum"].(float64); ok {
+			return min
+		}
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []any{}
+	case "object":
+		return map[string]any{}
+	default:
+		return "example"
+	}
+}
+
+// prefersHTML reports whether an Accept header ranks text/html above
+// application/json. Browsers send text/html first; curl and scripts get
+// JSON.
+func prefersHTML(accept string) bool {
+	htmlQ, jsonQ := -1.0, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "text/html":
+			htmlQ = max(htmlQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return htmlQ > 0 && htmlQ >= jsonQ
+}
+
+// serveInfoPage answers GET /mcp requests that don't open a stream with
+// the info page, as HTML for browsers and JSON otherwise.
+func (a *mcpApp) serveInfoPage(w http.ResponseWriter, r *http.Request) {
+	reg, err := a.registry(r.Context())
+	if err != nil {
+		log.Printf("Failed to build info page: %v", err)
+		http.Error(w, "Failed to build info page", http.StatusInternalServerError)
+		return
+	}
+	page := newInfoPage(reg)
+
+	w.Header().Add("Vary", "Accept")
+	if !prefersHTML(r.Header.Get("Accept")) {
+		writeJSON(w, http.StatusOK, page)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := infoPageTemplate.Execute(w, page); err != nil {
+		log.Printf("Failed to render info page: %v", err)
+	}
+}
+
+var infoPageTemplate = template.Must(template.New("info").Funcs(template.FuncMap{
+	"json": func(v any) string {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err.Error()
+		}
+		return string(data)
+	},
+	"hint": func(b *bool) bool { return b != nil && *b },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} {{.Version}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #222; }
+  h1 small { color: #888; font-weight: normal; }
+  section { margin-top: 2rem; }
+  article { border: 1px solid #e0e0e0; border-radius: 8px; padding: 0.5rem 1rem; margin: 1rem 0; }
+  code, pre { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 0.9em; }
+  pre { background: #f6f8fa; padding: 0.75rem; border-radius: 6px; overflow-x: auto; }
+  .hint { display: inline-block; font-size: 0.75em; padding: 0.1rem 0.5rem; border-radius: 1rem; background: #eef; margin-right: 0.25rem; }
+  .hint.destructive { background: #fdd; }
+  table { border-collapse: collapse; }
+  td { padding: 0.25rem 1rem 0.25rem 0; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>{{.Name}} <small>{{.Version}}</small></h1>
+<p>{{.Description}}. {{.Usage}}.</p>
+
+<section>
+<h2>Endpoints</h2>
+<table>
+{{range $name, $route := .Endpoints}}<tr><td><code>{{$name}}</code></td><td>{{$route}}</td></tr>
+{{end}}</table>
+</section>
+
+<section>
+<h2>Tools ({{len .Tools}})</h2>
+{{range .Tools}}<article id="tool-{{.Name}}">
+<h3><code>{{.Name}}</code>{{with .Title}} &middot; {{.}}{{end}}</h3>
+<p>
+{{if hint .Annotations.ReadOnlyHint}}<span class="hint">read-only</span>{{end}}
+{{if hint .Annotations.DestructiveHint}}<span class="hint destructive">destructive</span>{{end}}
+{{if hint .Annotations.IdempotentHint}}<span class="hint">idempotent</span>{{end}}
+{{if hint .Annotations.OpenWorldHint}}<span class="hint">open-world</span>{{end}}
+</p>
+<p>{{.Description}}</p>
+<details><summary>Input schema</summary><pre>{{json .InputSchema}}</pre></details>
+{{with .OutputSchema}}<details><summary>Output schema</summary><pre>{{json .}}</pre></details>{{end}}
+<p>Example call:</p>
+<pre>{{json .Example}}</pre>
+</article>
+{{end}}</section>
+
+<section>
+<h2>Resources ({{len .Resources}})</h2>
+<table>
+{{range .Resources}}<tr><td><code>{{.URI}}</code></td><td>{{.Name}}{{with .Description}}: {{.}}{{end}}{{with .MIMEType}} <small>({{.}})</small>{{end}}</td></tr>
+{{end}}</table>
+{{if .ResourceTemplates}}<h3>Resource templates</h3>
+<table>
+{{range .ResourceTemplates}}<tr><td><code>{{.URITemplate.Raw}}</code></td><td>{{.Name}}{{with .Description}}: {{.}}{{end}}{{with .MIMEType}} <small>({{.}})</small>{{end}}</td></tr>
+{{end}}</table>{{end}}
+</section>
+
+<section>
+<h2>Prompts ({{len .Prompts}})</h2>
+{{range .Prompts}}<article id="prompt-{{.Name}}">
+<h3><code>{{.Name}}</code></h3>
+<p>{{.Description}}</p>
+{{if .Arguments}}<table>
+{{range .Arguments}}<tr><td><code>{{.Name}}</code>{{if .Required}} (required){{end}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>{{end}}
+<p>Example call:</p>
+<pre>{{json .Example}}</pre>
+</article>
+{{end}}</section>
+</body>
+</html>
+`))
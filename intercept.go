@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// interceptedRequest is a JSON-RPC request for a method mcp-go does not
+// dispatch, answered by a handler in front of it.
+type interceptedRequest struct {
+	ID     any             `json:"id"`
+	Method mcp.MCPMethod   `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// parseIntercepted returns message as a request if it calls one of methods.
+// Notifications, which have no ID, are left to mcp-go.
+func parseIntercepted(message []byte, methods ...mcp.MCPMethod) (*interceptedRequest, bool) {
+	var request interceptedRequest
+	if json.Unmarshal(message, &request) != nil || request.ID == nil {
+		return nil, false
+	}
+	for _, method := range methods {
+		if request.Method == method {
+			return &request, true
+		}
+	}
+	return nil, false
+}
+
+// respondWithHooks runs handle between the hooks every other method goes
+// through, so intercepted requests are audited, counted and traced too.
+// hookRequest is the request in mcp-go's own shape. handle returns the
+// result, or a JSON-RPC error code and the error.
+func respondWithHooks(ctx context.Context, hooks *server.Hooks, request *interceptedRequest, hookRequest any, handle func() (any, int, error)) mcp.JSONRPCMessage {
+	id := mcp.NewRequestId(request.ID)
+	for _, hook := range hooks.OnBeforeAny {
+		hook(ctx, request.ID, request.Method, hookRequest)
+	}
+	result, code, err := handle()
+	if err != nil {
+		for _, hook := range hooks.OnError {
+			hook(ctx, request.ID, request.Method, hookRequest, err)
+		}
+		return jsonrpcError(id, code, err.Error())
+	}
+	for _, hook := range hooks.OnSuccess {
+		hook(ctx, request.ID, request.Method, hookRequest, result)
+	}
+	return mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: id, Result: result}
+}
+
+func jsonrpcError(id mcp.RequestId, code int, message string) mcp.JSONRPCError {
+	return mcp.JSONRPCError{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Error:   mcp.JSONRPCErrorDetails{Code: code, Message: message},
+	}
+}
+
+// internalRequestKey marks requests the server dispatches to itself, such
+// as the resource reads behind a prompt. The audit, metrics and tracing
+// hooks skip them, so they are recorded only as part of the client request
+// that caused them.
+type internalRequestKey struct{}
+
+// isInternalRequest reports whether ctx belongs to a request the server
+// dispatched to itself.
+func isInternalRequest(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalRequestKey{}).(bool)
+	return internal
+}
+
+// dispatchInternal sends a method call to s as an internal request and
+// returns its result, or an error carrying the JSON-RPC error message.
+func dispatchInternal(ctx context.Context, s *server.MCPServer, method mcp.MCPMethod, params any) (any, error) {
+	ctx = context.WithValue(ctx, internalRequestKey{}, true)
+	request, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      "internal",
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch response := s.HandleMessage(ctx, request).(type) {
+	case mcp.JSONRPCResponse:
+		return response.Result, nil
+	case mcp.JSONRPCError:
+		return nil, errors.New(response.Error.Message)
+	default:
+		return nil, fmt.Errorf("no response to %s", method)
+	}
+}
+
+// readRequestBody reads the body of r and puts it back for the next
+// handler.
+func readRequestBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err
+}
+
+// writeJSONRPC writes an intercepted response the way mcp-go writes its own.
+func writeJSONRPC(w http.ResponseWriter, response mcp.JSONRPCMessage) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
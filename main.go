@@ -2,17 +2,19 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 const (
@@ -21,86 +23,152 @@ const (
 	serverPort    = "8080"
 )
 
+// Widget templates served from ui/
+const (
+	listProductsWidgetFile    = "ui/list-products.html"
+	generateAssetWidgetFile   = "ui/generate_asset.html"
+	compareProductsWidgetFile = "ui/compare_products.html"
+	productDetailWidgetFile   = "ui/product_detail.html"
+)
+
 func main() {
-	// Create a new MCP server
-	s := server.NewMCPServer(
-		serverName,
-		serverVersion,
-		server.WithToolCapabilities(true),
-		server.WithResourceCapabilities(true, false),
-		server.WithPromptCapabilities(true),
-	)
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:]))
+	}
 
-	// Register tools
-	registerTools(s)
+	cfg, err := loadConfig(configPath())
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
 
-	// Register resources
-	registerResources(s)
+	shutdownTracing, err := setupTracing(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	shutdown := NewShutdownCoordinator(cfg.Shutdown)
+	shutdown.OnShutdown("traces", shutdownTracing)
 
-	// Register prompts
-	registerPrompts(s)
+	hooks := &server.Hooks{}
 
-	// Create streamable HTTP server (stateless mode for easier testing)
-	streamableServer := server.NewStreamableHTTPServer(s, server.WithStateLess(true))
+	// Audit log of every JSON-RPC request
+	var auditLogger *AuditLogger
+	if cfg.Audit.Enabled {
+		auditLogger, err = NewAuditLogger(cfg.Audit)
+		if err != nil {
+			log.Fatalf("Failed to open audit log: %v", err)
+		}
+		auditLogger.RegisterHooks(hooks)
+		shutdown.OnShutdown("audit log", func(context.Context) error {
+			return auditLogger.Close()
+		})
+	}
+	metrics.RegisterHooks(hooks)
+	RegisterTracingHooks(hooks)
+
+	app, err := newMCPServer(cfg, hooks)
+	if err != nil {
+		log.Fatalf("Failed to create MCP server: %v", err)
+	}
+	s := app.server
+	app.draining = shutdown.Draining
+	shutdown.OnDrain(func() {
+		app.subscriptions.Notify(serverInfoURI)
+		app.subscriptions.Notify(serverInfoTextURI)
+	})
+	if cfg.Server.Stateful {
+		// A stateless server has no sessions to send the warning to.
+		shutdown.OnDrain(func() { logShutdown(s, cfg.Shutdown.GracePeriod) })
+	}
+	go app.prompts.Watch(shutdown.BaseContext(nil))
+	go app.widgets.Watch(shutdown.BaseContext(nil))
+
+	// Create streamable HTTP server (stateless unless sessions are enabled)
+	streamableServer := server.NewStreamableHTTPServer(s, server.WithStateLess(!cfg.Server.Stateful))
 
 	// Setup HTTP server with custom mux
 	mux := http.NewServeMux()
 	
 	// Wrap MCP handler to support GET requests with info page
-	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			// Return info page for GET requests
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			info := map[string]interface{}{
-				"name":        serverName,
-				"version":     serverVersion,
-				"description": "MCP Server with Product Widget Tool",
-				"endpoints": map[string]string{
-					"mcp":    "/mcp (POST for MCP protocol)",
-					"health": "/health (GET for health check)",
-				},
-				"tools": []string{
-					"echo - Echoes back the input text",
-					"add - Adds two numbers together",
-					"get_time - Returns the current server time",
-					"list_products - Display an interactive product selection widget",
-					"generate_asset - Generate marketing and creative assets (Figma-style)",
-				},
-				"resources": []string{
-					"server://info - Server information",
-					"widget://list-products - Product selection widget",
-					"ui://widget/generate_asset.html - Asset generation widget (Figma-style)",
-				},
-				"usage": "Send POST requests with JSON-RPC 2.0 format to /mcp endpoint",
-			}
-			json.NewEncoder(w).Encode(info)
+	var mcpHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			// Describe the server to GET requests that don't open a stream
+			app.serveInfoPage(w, r)
 			return
 		}
-		// For POST requests, use the MCP handler
+		// For POST requests and notification streams, use the MCP handler
 		streamableServer.ServeHTTP(w, r)
 	})
+	mcpHandler = EndSessionOnDelete(s, mcpHandler)
+	mcpHandler = shutdown.RejectNewSessions(withDispatchSpan(app.subscriptions.Middleware(app.completer.Middleware(mcpHandler))))
+	if auditLogger != nil {
+		mcpHandler = auditLogger.Middleware(mcpHandler)
+	}
+	mcpHandler = NewOriginGuard(cfg.CORS, cfg.Server.DevMode).Middleware(mcpHandler)
+	if cfg.TLS.Enabled() && cfg.TLS.ClientAuth == "require" {
+		mcpHandler = RequireClientCert(mcpHandler)
+	}
+	mux.Handle("/mcp", withoutWriteDeadline(metrics.InstrumentHTTP("/mcp", mcpHandler)))
 	
 	// Add a health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+	mux.Handle("/health", metrics.InstrumentHTTP("/health", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
-	})
+	})))
+
+	// Liveness and readiness probes
+	health := NewHealthChecker(2 * time.Second)
+	health.Register("widget_files", widgetFilesCheck(listProductsWidgetFile, generateAssetWidgetFile, compareProductsWidgetFile, productDetailWidgetFile))
+	health.Register("shutdown", shutdown.HealthCheck())
+	mux.Handle("/livez", metrics.InstrumentHTTP("/livez", health.LiveHandler()))
+	mux.Handle("/readyz", metrics.InstrumentHTTP("/readyz", health.ReadyHandler()))
+
+	// Prometheus metrics
+	mux.Handle("/metrics", metrics.Handler())
+
+	// Published tool input/output schemas
+	schemas := SchemasHandler(s)
+	mux.Handle("/schemas", metrics.InstrumentHTTP("/schemas", schemas))
+	mux.Handle("/schemas/", metrics.InstrumentHTTP("/schemas", schemas))
 
 	httpServer := &http.Server{
-		Addr:         ":" + serverPort,
-		Handler:      mux,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:         cfg.addr(),
+		Handler:      TraceHTTP(mux),
+		ReadTimeout:  seconds(cfg.Timeouts.Read),
+		WriteTimeout: seconds(cfg.Timeouts.Write),
+		IdleTimeout:  seconds(cfg.Timeouts.Idle),
+		BaseContext:  shutdown.BaseContext,
+	}
+
+	// Serve HTTPS when a certificate is configured, reloading it on change
+	scheme := "http"
+	if cfg.TLS.Enabled() {
+		reloader, err := NewCertReloader(cfg.TLS)
+		if err != nil {
+			log.Fatalf("Failed to configure TLS: %v", err)
+		}
+		httpServer.TLSConfig = reloader.TLSConfig()
+		go reloader.Watch(shutdown.BaseContext(nil))
+		scheme = "https"
 	}
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting MCP server on port %s", serverPort)
-		log.Printf("MCP endpoint: http://localhost:%s/mcp", serverPort)
-		log.Printf("Health check: http://localhost:%s/health", serverPort)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Starting MCP server on %s", httpServer.Addr)
+		log.Printf("MCP endpoint: %s://localhost:%s/mcp", scheme, cfg.Server.Port)
+		log.Printf("Health check: %s://localhost:%s/health", scheme, cfg.Server.Port)
+		log.Printf("Liveness/readiness: %s://localhost:%s/livez, /readyz", scheme, cfg.Server.Port)
+		log.Printf("Metrics: %s://localhost:%s/metrics", scheme, cfg.Server.Port)
+		if auditLogger != nil {
+			log.Printf("Audit log: %s", cfg.Audit.Path)
+		}
+		var err error
+		if cfg.TLS.Enabled() {
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
@@ -111,223 +179,214 @@ func main() {
 	<-quit
 	log.Println("Shutting down server...")
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
-	}
+	// Drain in-flight requests, then flush the audit log and traces
+	shutdown.Shutdown(httpServer)
 
 	log.Println("Server exited")
 }
 
+// mcpApp is the MCP server together with the parts that run alongside
+// mcp-go: the completer and subscriptions in front of it, and the prompt
+// library and widget watcher feeding it.
+type mcpApp struct {
+	server        *server.MCPServer
+	completer     *Completer
+	subscriptions *Subscriptions
+	prompts       *PromptLibrary
+	widgets       *WidgetWatcher
+	orders        *OrderStore
+
+	// draining reports whether the server is shutting down. main sets it
+	// before serving; it is nil for in-process replays.
+	draining func() bool
+}
+
+// newMCPServer creates the MCP server with every tool, resource and prompt
+// registered. It is shared by the HTTP server and the in-process replay.
+func newMCPServer(cfg *Config, hooks *server.Hooks) (*mcpApp, error) {
+	if cfg.Timeouts.Elicitation > 0 {
+		elicitationTimeout = seconds(cfg.Timeouts.Elicitation)
+	}
+
+	s := server.NewMCPServer(
+		serverName,
+		serverVersion,
+		server.WithToolCapabilities(true),
+		// Subscriptions and list changes need sessions to notify.
+		server.WithResourceCapabilities(cfg.Server.Stateful, cfg.Server.Stateful),
+		server.WithPromptCapabilities(true),
+		server.WithElicitation(),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(traceToolHandler),
+		server.WithToolHandlerMiddleware(validateToolOutput(cfg.Server.DevMode)),
+		server.WithToolHandlerMiddleware(confirmDestructive),
+		server.WithResourceHandlerMiddleware(traceResourceHandler),
+	)
+	app := &mcpApp{
+		server:        s,
+		completer:     NewCompleter(hooks),
+		subscriptions: NewSubscriptions(s, hooks),
+	}
+
+	// Register tools
+	registerTools(s)
+	registerCalculator(s)
+	registerTimeTools(s)
+	registerDiagnostics(s)
+	registerComparison(s)
+	registerProductDetail(s)
+	registerRecommendations(s)
+
+	// Register resources
+	registerServerInfo(app, cfg)
+	registerChecklists(s, app.completer)
+	registerCatalog(s, app.completer)
+	app.orders = NewOrderStore(func(id string) { app.subscriptions.Notify("order://" + id) })
+	registerOrders(s, app.completer, app.orders)
+	app.widgets = NewWidgetWatcher(cfg.Widgets, s, app.subscriptions)
+
+	// Load prompts from the prompt library
+	app.prompts = NewPromptLibrary(cfg.Prompts, s, app.completer)
+	if err := app.prompts.Load(); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+func (a *mcpApp) isDraining() bool {
+	return a.draining != nil && a.draining()
+}
+
+// echoArgs are the arguments of the echo tool.
+type echoArgs struct {
+	Message string `json:"message" validate:"required" description:"The message to echo back"`
+}
+
+// addArgs are the arguments of the add tool.
+type addArgs struct {
+	A float64 `json:"a" validate:"required" description:"First number"`
+	B float64 `json:"b" validate:"required" description:"Second number"`
+}
+
+// generateAssetArgs are the arguments of the generate_asset tool.
+type generateAssetArgs struct {
+	AssetType   string `json:"asset_type" validate:"required,max=64" description:"Type of asset to generate (e.g., 'social_media_post', 'banner', 'business_card', 'flyer')"`
+	Description string `json:"description" validate:"max=2000" description:"Description of the asset requirements"`
+}
+
+// listProductsOutput is the structuredContent of list_products.
+type listProductsOutput struct {
+	Products []Product `json:"products" validate:"required" description:"Products to display"`
+}
+
+// generatedAsset is one asset in the generate_asset result.
+type generatedAsset struct {
+	ID          string   `json:"id" validate:"required" description:"Asset identifier"`
+	Name        string   `json:"name" validate:"required" description:"Display name"`
+	Type        string   `json:"type" validate:"required" description:"Format and dimensions"`
+	Description string   `json:"description" description:"One-line summary"`
+	Icon        string   `json:"icon" description:"Emoji shown next to the name"`
+	Preview     string   `json:"preview" description:"Preview image URL"`
+	Tags        []string `json:"tags" description:"Labels shown as chips"`
+}
+
+// generateAssetOutput is the structuredContent of generate_asset.
+type generateAssetOutput struct {
+	Message     string           `json:"message" validate:"required" description:"Headline shown above the assets"`
+	AssetType   string           `json:"asset_type" validate:"required" description:"Requested asset type"`
+	Description string           `json:"description" description:"Requested description"`
+	Assets      []generatedAsset `json:"assets" validate:"required" description:"Generated assets"`
+}
+
 func registerTools(s *server.MCPServer) {
 	// Example tool: Echo tool that returns the input
 	echoTool := mcp.NewTool("echo",
 		mcp.WithDescription("Echoes back the input text"),
-		mcp.WithString("message",
-			mcp.Required(),
-			mcp.Description("The message to echo back"),
-		),
+		withHints(toolHints{Title: "Echo", ReadOnly: true, Idempotent: true}),
+		withArguments[echoArgs](),
 	)
 
-	s.AddTool(echoTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]interface{})
-		if !ok {
-			return mcp.NewToolResultError("invalid arguments"), nil
-		}
-		
-		message, ok := args["message"].(string)
-		if !ok {
-			return mcp.NewToolResultError("message must be a string"), nil
-		}
-
-		return mcp.NewToolResultText(fmt.Sprintf("Echo: %s", message)), nil
-	})
+	s.AddTool(echoTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(fmt.Sprintf("Echo: %s", args.Message)), nil
+	}))
 
 	// Example tool: Add numbers
 	addTool := mcp.NewTool("add",
-		mcp.WithDescription("Adds two numbers together"),
-		mcp.WithNumber("a",
-			mcp.Required(),
-			mcp.Description("First number"),
-		),
-		mcp.WithNumber("b",
-			mcp.Required(),
-			mcp.Description("Second number"),
-		),
+		mcp.WithDescription("Adds two numbers together. Deprecated: use calculate, which evaluates whole expressions"),
+		withHints(toolHints{Title: "Add Numbers", ReadOnly: true, Idempotent: true}),
+		withArguments[addArgs](),
 	)
 
-	s.AddTool(addTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]interface{})
-		if !ok {
-			return mcp.NewToolResultError("invalid arguments"), nil
-		}
-		
-		a, okA := args["a"].(float64)
-		b, okB := args["b"].(float64)
-		
-		if !okA || !okB {
-			return mcp.NewToolResultError("both a and b must be numbers"), nil
-		}
-
-		result := a + b
+	s.AddTool(addTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args addArgs) (*mcp.CallToolResult, error) {
+		result := args.A + args.B
 		return mcp.NewToolResultText(fmt.Sprintf("Result: %.2f", result)), nil
-	})
-
-	// Example tool: Get current time
-	timeTool := mcp.NewTool("get_time",
-		mcp.WithDescription("Returns the current server time"),
-	)
-
-	s.AddTool(timeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		currentTime := time.Now().Format(time.RFC3339)
-		return mcp.NewToolResultText(fmt.Sprintf("Current time: %s", currentTime)), nil
-	})
+	}))
 
 	// Product listing tool with HTML widget
 	listProductsTool := mcp.NewTool("list_products",
 		mcp.WithDescription("Display an interactive product selection widget"),
+		withHints(toolHints{Title: "List Products", ReadOnly: true, Idempotent: true}),
+		withOutput[listProductsOutput](),
 	)
 
 	s.AddTool(listProductsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		// Product data (structured for the widget)
-		products := []map[string]interface{}{
-			{
-				"name":        "Premium Widget",
-				"price":       "99.99",
-				"priceId":     "price_premium_widget",
-				"description": "Our flagship product with advanced features and premium support",
-				"image":       "https://images.unsplash.com/photo-1526374965328-7f61d4dc18c5?w=150&h=150&fit=crop",
-			},
-			{
-				"name":        "Standard Package",
-				"price":       "49.99",
-				"priceId":     "price_standard_package",
-				"description": "Perfect for small teams with essential features included",
-				"image":       "https://images.unsplash.com/photo-1460925895917-afdab827c52f?w=150&h=150&fit=crop",
-			},
-			{
-				"name":        "Basic Starter",
-				"price":       "29.99",
-				"priceId":     "price_basic_starter",
-				"description": "Get started with our basic plan, ideal for individuals",
-				"image":       "https://images.unsplash.com/photo-1484480974693-6ca0a78fb36b?w=150&h=150&fit=crop",
-			},
-			{
-				"name":        "Enterprise Solution",
-				"price":       "199.99",
-				"priceId":     "price_enterprise_solution",
-				"description": "Complete enterprise solution with dedicated support and custom features",
-				"image":       "https://images.unsplash.com/photo-1551288049-bebda4e38f71?w=150&h=150&fit=crop",
-			},
-		}
+		products := catalogSummaries()
 
 		// Build rich text response (works in Cursor and all clients)
 		textResponse := "🛍️ **Available Products**\n\n"
 		for i, p := range products {
-			textResponse += fmt.Sprintf("**%d. %s** - $%s\n", i+1, p["name"], p["price"])
-			textResponse += fmt.Sprintf("   %s\n\n", p["description"])
+			textResponse += fmt.Sprintf("**%d. %s** - $%s\n", i+1, p.Name, p.Price)
+			textResponse += fmt.Sprintf("   %s\n\n", p.Description)
 		}
 		textResponse += "---\n💡 *Select a product to proceed with your order.*"
 
-		// Read the HTML widget file for ChatGPT
-		htmlContent, err := os.ReadFile("ui/list-products.html")
-		if err != nil {
-			// If HTML file not found, just return text
-			return mcp.NewToolResultText(textResponse), nil
-		}
-
-		// Create metadata for CSP
-		metadata := map[string]interface{}{
-			"openai/widgetCSP": map[string]interface{}{
-				"connect_domains":  []string{"https://images.unsplash.com"},
-				"resource_domains": []string{"https://images.unsplash.com"},
-			},
-		}
-
-		// Create embedded resource with the HTML widget
-		resource := mcp.TextResourceContents{
-			URI:      "widget://list-products",
-			MIMEType: "text/html+skybridge",
-			Text:     string(htmlContent),
-			Meta:     metadata,
-		}
-
 		// Structured content for the widget (ChatGPT passes this to the HTML)
-		structuredContent := map[string]interface{}{
-			"products": products,
-		}
+		structuredContent := listProductsOutput{Products: products}
 
-		// Return result with text, embedded resource, AND structuredContent
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: textResponse,
-				},
-				mcp.NewEmbeddedResource(resource),
-			},
-			StructuredContent: structuredContent,
-		}, nil
+		return widgetResult(ctx, "widget://list-products", listProductsWidgetFile, textResponse, structuredContent), nil
 	})
 
 	// Asset generation tool (similar to Figma in ChatGPT)
 	generateAssetTool := mcp.NewTool("generate_asset",
 		mcp.WithDescription("Generates marketing and creative assets in Figma Buzz, including but not limited to social media posts, banners, digital ads, posters, hiring materials, event materials, one-pagers, or flyers, greeting cards, invitations, resumes"),
-		mcp.WithString("asset_type",
-			mcp.Required(),
-			mcp.Description("Type of asset to generate (e.g., 'social_media_post', 'banner', 'business_card', 'flyer')"),
-		),
-		mcp.WithString("description",
-			mcp.Description("Description of the asset requirements"),
-		),
+		withHints(toolHints{Title: "Generate Asset", OpenWorld: true}),
+		withArguments[generateAssetArgs](),
+		withOutput[generateAssetOutput](),
 	)
 
-	s.AddTool(generateAssetTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		args, ok := request.Params.Arguments.(map[string]interface{})
-		if !ok {
-			return mcp.NewToolResultError("invalid arguments"), nil
-		}
-
-		assetType, ok := args["asset_type"].(string)
-		if !ok || assetType == "" {
-			return mcp.NewToolResultError("asset_type is required"), nil
-		}
-
-		description := ""
-		if desc, ok := args["description"].(string); ok {
-			description = desc
-		}
+	s.AddTool(generateAssetTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args generateAssetArgs) (*mcp.CallToolResult, error) {
+		assetType := args.AssetType
+		description := args.Description
 
 		// Asset data (structured for the widget)
-		assets := []map[string]interface{}{
+		assets := []generatedAsset{
 			{
-				"id":          "asset_001",
-				"name":        "Social Media Post",
-				"type":        "Instagram Post (1080x1080)",
-				"description": "Eye-catching social media post with modern gradient design",
-				"icon":        "📱",
-				"preview":     "https://images.unsplash.com/photo-1611162617474-5b21e879e113?w=400&h=400&fit=crop",
-				"tags":        []string{"Social Media", "Instagram", "Marketing"},
+				ID:          "asset_001",
+				Name:        "Social Media Post",
+				Type:        "Instagram Post (1080x1080)",
+				Description: "Eye-catching social media post with modern gradient design",
+				Icon:        "📱",
+				Preview:     "https://images.unsplash.com/photo-1611162617474-5b21e879e113?w=400&h=400&fit=crop",
+				Tags:        []string{"Social Media", "Instagram", "Marketing"},
 			},
 			{
-				"id":          "asset_002",
-				"name":        "Banner Ad",
-				"type":        "Web Banner (728x90)",
-				"description": "Professional banner ad for website campaigns",
-				"icon":        "🎯",
-				"preview":     "https://images.unsplash.com/photo-1557838923-2985c318be48?w=728&h=200&fit=crop",
-				"tags":        []string{"Banner", "Advertising", "Web"},
+				ID:          "asset_002",
+				Name:        "Banner Ad",
+				Type:        "Web Banner (728x90)",
+				Description: "Professional banner ad for website campaigns",
+				Icon:        "🎯",
+				Preview:     "https://images.unsplash.com/photo-1557838923-2985c318be48?w=728&h=200&fit=crop",
+				Tags:        []string{"Banner", "Advertising", "Web"},
 			},
 			{
-				"id":          "asset_003",
-				"name":        "Business Card",
-				"type":        "Print Ready (3.5x2 in)",
-				"description": "Modern business card design with clean layout",
-				"icon":        "💼",
-				"preview":     "https://images.unsplash.com/photo-1589829545856-d10d557cf95f?w=400&h=250&fit=crop",
-				"tags":        []string{"Print", "Business", "Professional"},
+				ID:          "asset_003",
+				Name:        "Business Card",
+				Type:        "Print Ready (3.5x2 in)",
+				Description: "Modern business card design with clean layout",
+				Icon:        "💼",
+				Preview:     "https://images.unsplash.com/photo-1589829545856-d10d557cf95f?w=400&h=250&fit=crop",
+				Tags:        []string{"Print", "Business", "Professional"},
 			},
 		}
 
@@ -339,212 +398,34 @@ func registerTools(s *server.MCPServer) {
 		}
 		textResponse += "\n**Generated Assets:**\n\n"
 		for i, a := range assets {
-			textResponse += fmt.Sprintf("%s **%d. %s**\n", a["icon"], i+1, a["name"])
-			textResponse += fmt.Sprintf("   Format: %s\n\n", a["type"])
+			textResponse += fmt.Sprintf("%s **%d. %s**\n", a.Icon, i+1, a.Name)
+			textResponse += fmt.Sprintf("   Format: %s\n\n", a.Type)
 		}
 		textResponse += "---\n✅ *Assets are ready for download and editing.*"
 
-		// Read the HTML widget file for ChatGPT
-		htmlContent, err := os.ReadFile("ui/generate_asset.html")
-		if err != nil {
-			// If HTML file not found, just return text
-			return mcp.NewToolResultText(textResponse), nil
-		}
-
-		// Create metadata for CSP
-		metadata := map[string]interface{}{
-			"openai/widgetCSP": map[string]interface{}{
-				"connect_domains":  []string{"https://images.unsplash.com"},
-				"resource_domains": []string{"https://images.unsplash.com"},
-			},
-		}
-
 		// Structured content for the widget (ChatGPT passes this to the HTML)
-		structuredContent := map[string]interface{}{
-			"message":     fmt.Sprintf("Figma assets created for: %s", assetType),
-			"asset_type":  assetType,
-			"description": description,
-			"assets":      assets,
-		}
-
-		// Create embedded resource with the HTML widget
-		resource := mcp.TextResourceContents{
-			URI:      "ui://widget/generate_asset.html",
-			MIMEType: "text/html+skybridge",
-			Text:     string(htmlContent),
-			Meta:     metadata,
+		structuredContent := generateAssetOutput{
+			Message:     fmt.Sprintf("Figma assets created for: %s", assetType),
+			AssetType:   assetType,
+			Description: description,
+			Assets:      assets,
 		}
 
-		// Return result with text, embedded resource, AND structuredContent
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{
-				mcp.TextContent{
-					Type: "text",
-					Text: textResponse,
-				},
-				mcp.NewEmbeddedResource(resource),
-			},
-			StructuredContent: structuredContent,
-		}, nil
-	})
+		return widgetResult(ctx, "ui://widget/generate_asset.html", generateAssetWidgetFile, textResponse, structuredContent), nil
+	}))
 }
 
-func registerResources(s *server.MCPServer) {
-	// Example resource: Server info
-	serverInfoResource := mcp.Resource{
-		URI:         "server://info",
-		Name:        "Server Information",
-		Description: "Information about this MCP server",
-		MIMEType:    "text/plain",
-	}
-
-	s.AddResource(serverInfoResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		info := fmt.Sprintf("Server: %s\nVersion: %s\nTime: %s",
-			serverName,
-			serverVersion,
-			time.Now().Format(time.RFC3339),
-		)
-		return []mcp.ResourceContents{
-			mcp.TextResourceContents{
-				URI:      request.Params.URI,
-				MIMEType: "text/plain",
-				Text:     info,
-			},
-		}, nil
-	})
-
-	// Product listing widget resource
-	productWidgetResource := mcp.Resource{
-		URI:         "widget://list-products",
-		Name:        "Product Selection Widget",
-		Description: "Interactive HTML widget for selecting products",
-		MIMEType:    "text/html+skybridge",
+// readWidgetHTML loads a widget template from ui/, counting failures so a
+// missing or unreadable file shows up on /metrics.
+func readWidgetHTML(ctx context.Context, path string) ([]byte, error) {
+	_, span := startSpan(ctx, "widget.read", attribute.String("widget.file", path))
+	defer span.End()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		metrics.WidgetReadFailed(path)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
-
-	s.AddResource(productWidgetResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		// Read the HTML widget file
-		htmlContent, err := os.ReadFile("ui/list-products.html")
-		if err != nil {
-			return nil, fmt.Errorf("failed to read widget HTML: %v", err)
-		}
-
-		// Create metadata for CSP (Content Security Policy) to allow image loading
-		metadata := map[string]interface{}{
-			"openai/widgetCSP": map[string]interface{}{
-				"connect_domains":  []string{"https://images.unsplash.com"},
-				"resource_domains": []string{"https://images.unsplash.com"},
-			},
-		}
-
-		return []mcp.ResourceContents{
-			mcp.TextResourceContents{
-				URI:      request.Params.URI,
-				MIMEType: "text/html+skybridge",
-				Text:     string(htmlContent),
-				Meta:     metadata,
-			},
-		}, nil
-	})
-
-	// Asset generation widget resource
-	assetWidgetResource := mcp.Resource{
-		URI:         "ui://widget/generate_asset.html",
-		Name:        "Asset Generation Widget",
-		Description: "Interactive HTML widget for displaying generated assets (Figma-style)",
-		MIMEType:    "text/html+skybridge",
-	}
-
-	s.AddResource(assetWidgetResource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-		// Read the HTML widget file
-		htmlContent, err := os.ReadFile("ui/generate_asset.html")
-		if err != nil {
-			return nil, fmt.Errorf("failed to read asset widget HTML: %v", err)
-		}
-
-		// Create metadata for CSP (Content Security Policy) to allow image loading
-		metadata := map[string]interface{}{
-			"openai/widgetCSP": map[string]interface{}{
-				"connect_domains":  []string{"https://images.unsplash.com"},
-				"resource_domains": []string{"https://images.unsplash.com"},
-			},
-		}
-
-		return []mcp.ResourceContents{
-			mcp.TextResourceContents{
-				URI:      request.Params.URI,
-				MIMEType: "text/html+skybridge",
-				Text:     string(htmlContent),
-				Meta:     metadata,
-			},
-		}, nil
-	})
+	return content, err
 }
-
-func registerPrompts(s *server.MCPServer) {
-	// Example prompt: Greeting prompt
-	greetingPrompt := mcp.Prompt{
-		Name:        "greeting",
-		Description: "Generate a personalized greeting",
-		Arguments: []mcp.PromptArgument{
-			{
-				Name:        "name",
-				Description: "Name of the person to greet",
-				Required:    true,
-			},
-		},
-	}
-
-	s.AddPrompt(greetingPrompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
-		name, ok := request.Params.Arguments["name"]
-		if !ok || name == "" {
-			return nil, fmt.Errorf("name argument is required")
-		}
-
-		return &mcp.GetPromptResult{
-			Description: "A personalized greeting",
-			Messages: []mcp.PromptMessage{
-				{
-					Role: mcp.RoleUser,
-					Content: mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Hello, %s! Welcome to our MCP server.", name),
-					},
-				},
-			},
-		}, nil
-	})
-
-	// Example prompt: Code review prompt
-	codeReviewPrompt := mcp.Prompt{
-		Name:        "code_review",
-		Description: "Generate a code review prompt",
-		Arguments: []mcp.PromptArgument{
-			{
-				Name:        "language",
-				Description: "Programming language",
-				Required:    true,
-			},
-		},
-	}
-
-	s.AddPrompt(codeReviewPrompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
-		language, ok := request.Params.Arguments["language"]
-		if !ok || language == "" {
-			return nil, fmt.Errorf("language argument is required")
-		}
-
-		return &mcp.GetPromptResult{
-			Description: "Code review guidelines",
-			Messages: []mcp.PromptMessage{
-				{
-					Role: mcp.RoleUser,
-					Content: mcp.TextContent{
-						Type: "text",
-						Text: fmt.Sprintf("Please review the following %s code for:\n1. Best practices\n2. Security issues\n3. Performance concerns\n4. Code style", language),
-					},
-				},
-			},
-		}, nil
-	})
-}
-
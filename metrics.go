@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus collectors exported on /metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	mcpRequests        *prometheus.CounterVec
+	mcpDuration        *prometheus.HistogramVec
+	mcpErrors          *prometheus.CounterVec
+	activeSessions     prometheus.Gauge
+	inFlight           prometheus.Gauge
+	httpRequests       *prometheus.CounterVec
+	httpDuration       *prometheus.HistogramVec
+	widgetReadFailures *prometheus.CounterVec
+}
+
+// metrics is shared by the HTTP layer, the MCP hooks and the handlers that
+// read widget files.
+var metrics = NewMetrics()
+
+// NewMetrics creates the collectors on a private registry together with the
+// standard Go runtime and process collectors.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		mcpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_requests_total",
+			Help: "JSON-RPC requests handled, by method, tool/resource/prompt and outcome.",
+		}, []string{"method", "target", "outcome"}),
+		mcpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mcp_request_duration_seconds",
+			Help:    "Time spent dispatching JSON-RPC requests, by method and tool/resource/prompt.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "target"}),
+		mcpErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_errors_total",
+			Help: "JSON-RPC errors and tool error results, by method and error type.",
+		}, []string{"method", "type"}),
+		activeSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mcp_active_sessions",
+			Help: "MCP sessions currently registered with the server.",
+		}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "mcp_inflight_requests",
+			Help: "Requests to /mcp currently being served.",
+		}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests served, by route, method and status code.",
+		}, []string{"route", "method", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency, by route and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		widgetReadFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "widget_read_failures_total",
+			Help: "Failures reading widget HTML files from ui/, by file.",
+		}, []string{"file"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.mcpRequests,
+		m.mcpDuration,
+		m.mcpErrors,
+		m.activeSessions,
+		m.inFlight,
+		m.httpRequests,
+		m.httpDuration,
+		m.widgetReadFailures,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// WidgetReadFailed records a failed read of a widget file.
+func (m *Metrics) WidgetReadFailed(file string) {
+	m.widgetReadFailures.WithLabelValues(file).Inc()
+}
+
+type metricsStartKey struct{}
+
+// InstrumentHTTP wraps a route handler with request counters and latency.
+// Requests to /mcp are also tracked as in flight and timed per JSON-RPC
+// method by the hooks registered in RegisterHooks.
+func (m *Metrics) InstrumentHTTP(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		if route == "/mcp" {
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+			r = r.WithContext(context.WithValue(r.Context(), metricsStartKey{}, start))
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		m.httpRequests.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+		m.httpDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+	})
+}
+
+// RegisterHooks records per-method counters, latency, errors and sessions.
+func (m *Metrics) RegisterHooks(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		m.activeSessions.Inc()
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		m.activeSessions.Dec()
+	})
+
+	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
+		if isInternalRequest(ctx) {
+			return
+		}
+		target := metricsTarget(ctx, message)
+		outcome := outcomeSuccess
+		if res, ok := result.(*mcp.CallToolResult); ok && res.IsError {
+			outcome = outcomeToolError
+			m.mcpErrors.WithLabelValues(string(method), outcomeToolError).Inc()
+		}
+		m.observe(ctx, string(method), target, outcome)
+	})
+
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		if isInternalRequest(ctx) {
+			return
+		}
+		kind := errorType(err)
+		target := metricsTarget(ctx, message)
+		if strings.HasSuffix(kind, "_not_found") {
+			// Unknown names come straight from the client; keep them out of
+			// the label set so they cannot blow up its cardinality.
+			target = ""
+		}
+		m.mcpErrors.WithLabelValues(string(method), kind).Inc()
+		m.observe(ctx, string(method), target, outcomeError)
+	})
+}
+
+func (m *Metrics) observe(ctx context.Context, method, target, outcome string) {
+	m.mcpRequests.WithLabelValues(method, target, outcome).Inc()
+	if start, ok := ctx.Value(metricsStartKey{}).(time.Time); ok {
+		m.mcpDuration.WithLabelValues(method, target).Observe(time.Since(start).Seconds())
+	}
+}
+
+// metricsTarget returns the tool, resource or prompt a request addresses.
+func metricsTarget(ctx context.Context, message any) string {
+	switch req := message.(type) {
+	case *mcp.CallToolRequest:
+		return req.Params.Name
+	case *mcp.ReadResourceRequest:
+		return metricsResource(req.Params.URI)
+	case *mcp.SubscribeRequest:
+		return metricsResource(req.Params.URI)
+	case *mcp.UnsubscribeRequest:
+		return metricsResource(req.Params.URI)
+	case *mcp.GetPromptRequest:
+		return req.Params.Name
+	case *mcp.CompleteRequest:
+		switch ref := req.Params.Ref.(type) {
+		case mcp.PromptReference:
+			if unknown, _ := ctx.Value(unknownPromptKey{}).(bool); unknown {
+				return "other"
+			}
+			return ref.Name
+		case mcp.ResourceReference:
+			return metricsResource(ref.URI)
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
+// metricsResourceTemplates are the resource templates reported as
+// themselves on /metrics.
+var metricsResourceTemplates = []string{productTemplate, categoryTemplate, orderTemplate, checklistTemplate}
+
+// metricsResource returns the label of a resource URI: the URI of a static
+// resource, the template it matches or "other". URIs come from the client,
+// so they are never used as labels directly.
+func metricsResource(uri string) string {
+	if uri == serverInfoURI || uri == serverInfoTextURI {
+		return uri
+	}
+	for _, w := range widgets {
+		if uri == w.resource.URI {
+			return uri
+		}
+	}
+	for _, template := range metricsResourceTemplates {
+		if matchesTemplate(template, uri) {
+			return template
+		}
+	}
+	return "other"
+}
+
+// matchesTemplate reports whether uri fills the single variable of
+// template with a non-empty value that has no slash.
+func matchesTemplate(template, uri string) bool {
+	start, end := strings.Index(template, "{"), strings.Index(template, "}")
+	if start < 0 || end < start {
+		return template == uri
+	}
+	prefix, suffix := template[:start], template[end+1:]
+	if !strings.HasPrefix(uri, prefix) || !strings.HasSuffix(uri, suffix) || len(uri) <= len(prefix)+len(suffix) {
+		return false
+	}
+	return !strings.Contains(uri[len(prefix):len(uri)-len(suffix)], "/")
+}
+
+// errorType classifies dispatcher errors for the mcp_errors_total label.
+func errorType(err error) string {
+	var parseErr *server.UnparsableMessageError
+	switch {
+	case errors.As(err, &parseErr):
+		return "parse_error"
+	case errors.Is(err, server.ErrToolNotFound):
+		return "tool_not_found"
+	case errors.Is(err, server.ErrResourceNotFound):
+		return "resource_not_found"
+	case errors.Is(err, server.ErrPromptNotFound):
+		return "prompt_not_found"
+	case errors.Is(err, server.ErrUnsupported):
+		return "unsupported"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	default:
+		return "internal"
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestMetricsResource(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"server://info", "server://info"},
+		{"widget://list-products", "widget://list-products"},
+		{"product://price_basic_starter", productTemplate},
+		{"product://anything-a-client-sends", productTemplate},
+		{"catalog://category/plans", categoryTemplate},
+		{"catalog://category/a/b", "other"},
+		{"order://ord_1001", orderTemplate},
+		{"review://checklist/go", checklistTemplate},
+		{"product://", "other"},
+		{"file:///etc/passwd", "other"},
+		{"", "other"},
+	}
+	for _, tt := range tests {
+		if got := metricsResource(tt.uri); got != tt.want {
+			t.Errorf("metricsResource(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
+
+func TestMetricsTargetCompletion(t *testing.T) {
+	hooks := &server.Hooks{}
+	var targets []string
+	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
+		targets = append(targets, metricsTarget(ctx, message))
+	})
+	completer := NewCompleter(hooks)
+	completer.Prompt("greeting", "name", completeFrom("Ann"))
+	completer.ReplacePrompts(map[string]map[string]CompletionFunc{"code_review": {}})
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{`{"type": "ref/prompt", "name": "greeting"}`, "greeting"},
+		{`{"type": "ref/prompt", "name": "code_review"}`, "code_review"},
+		{`{"type": "ref/prompt", "name": "made-up-by-the-client"}`, "other"},
+		{`{"type": "ref/resource", "uri": "order://{id}"}`, orderTemplate},
+		{`{"type": "ref/resource", "uri": "order://ord_9"}`, orderTemplate},
+	}
+	for _, tt := range tests {
+		targets = nil
+		message := `{"jsonrpc": "2.0", "id": 1, "method": "completion/complete", "params": {"ref": ` + tt.ref + `, "argument": {"name": "x", "value": ""}}}`
+		if _, ok := completer.HandleMessage(context.Background(), []byte(message)); !ok {
+			t.Fatalf("HandleMessage(%s) was not handled", tt.ref)
+		}
+		if len(targets) != 1 || targets[0] != tt.want {
+			t.Errorf("target of %s = %q, want %q", tt.ref, targets, tt.want)
+		}
+	}
+}
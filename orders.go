@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// orderTemplate is the URI template of orders.
+const orderTemplate = "order://{id}"
+
+// Order is a customer order for catalog products.
+type Order struct {
+	ID        string      `json:"id"`
+	Status    string      `json:"status"` // pending, paid, shipped or cancelled
+	Items     []OrderItem `json:"items"`
+	Total     string      `json:"total"`
+	Currency  string      `json:"currency"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// OrderItem is one line of an order.
+type OrderItem struct {
+	PriceID   string `json:"priceId"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice string `json:"unitPrice"`
+}
+
+// OrderStore holds orders in memory.
+type OrderStore struct {
+	mu     sync.RWMutex
+	orders map[string]Order
+
+	// onChange is called with the ID of every order Put.
+	onChange func(id string)
+}
+
+// NewOrderStore creates a store holding the sample orders. onChange, if
+// set, is called whenever an order is added or updated afterwards.
+func NewOrderStore(onChange func(id string)) *OrderStore {
+	store := &OrderStore{orders: make(map[string]Order)}
+	for _, order := range sampleOrders() {
+		store.Put(order)
+	}
+	store.onChange = onChange
+	return store
+}
+
+// Get returns the order with id.
+func (o *OrderStore) Get(id string) (Order, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	order, ok := o.orders[id]
+	return order, ok
+}
+
+// IDs returns the ID of every order, sorted.
+func (o *OrderStore) IDs() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	ids := make([]string, 0, len(o.orders))
+	for id := range o.orders {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Put adds or replaces order.
+func (o *OrderStore) Put(order Order) {
+	o.mu.Lock()
+	o.orders[order.ID] = order
+	o.mu.Unlock()
+	if o.onChange != nil {
+		o.onChange(order.ID)
+	}
+}
+
+// newOrder builds an order for quantities of catalog products, keyed by
+// price ID, and totals it.
+func newOrder(id, status string, createdAt time.Time, quantities map[string]int) (Order, error) {
+	order := Order{ID: id, Status: status, Currency: "USD", CreatedAt: createdAt}
+	var total int64
+	for priceID, quantity := range quantities {
+		product, ok := findProduct(priceID)
+		if !ok {
+			return Order{}, fmt.Errorf("product %q not found", priceID)
+		}
+		cents, err := parseCents(product.Price)
+		if err != nil {
+			return Order{}, err
+		}
+		total += cents * int64(quantity)
+		order.Items = append(order.Items, OrderItem{
+			PriceID:   priceID,
+			Name:      product.Name,
+			Quantity:  quantity,
+			UnitPrice: product.Price,
+		})
+	}
+	sort.Slice(order.Items, func(i, j int) bool { return order.Items[i].PriceID < order.Items[j].PriceID })
+	order.Total = formatCents(total)
+	return order, nil
+}
+
+// parseCents parses a catalog price such as "49.99" into cents.
+func parseCents(price string) (int64, error) {
+	whole, frac, _ := strings.Cut(price, ".")
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || len(frac) != 2 {
+		return 0, fmt.Errorf("invalid price %q", price)
+	}
+	return cents, nil
+}
+
+// formatCents formats cents as a catalog price.
+func formatCents(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+// sampleOrders are the demo orders the store starts with.
+func sampleOrders() []Order {
+	day := time.Date(2025, 12, 1, 9, 30, 0, 0, time.UTC)
+	var orders []Order
+	for _, o := range []struct {
+		id         string
+		status     string
+		createdAt  time.Time
+		quantities map[string]int
+	}{
+		{"ord_1001", "shipped", day, map[string]int{"price_premium_widget": 1, "price_basic_starter": 2}},
+		{"ord_1002", "paid", day.AddDate(0, 0, 3), map[string]int{"price_standard_package": 1}},
+		{"ord_1003", "pending", day.AddDate(0, 0, 7), map[string]int{"price_enterprise_solution": 1}},
+	} {
+		order, err := newOrder(o.id, o.status, o.createdAt, o.quantities)
+		if err != nil {
+			panic(err) // the sample data must match the catalog
+		}
+		orders = append(orders, order)
+	}
+	return orders
+}
+
+// registerOrders adds the order resource template.
+func registerOrders(s *server.MCPServer, completer *Completer, orders *OrderStore) {
+	s.AddResourceTemplate(mcp.NewResourceTemplate(orderTemplate, "Order",
+		mcp.WithTemplateDescription("An order with its items, total and status as JSON"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		id := templateVariable(request, "id")
+		done := traceLookup(ctx, "orders.get", attribute.String("order.id", id))
+		order, ok := orders.Get(id)
+		done(ok)
+		if !ok {
+			return nil, fmt.Errorf("order %q not found", id)
+		}
+		return jsonResource(request.Params.URI, order)
+	})
+	completer.Resource(orderTemplate, "id", func(ctx context.Context, value string, arguments map[string]string) []string {
+		return completeFrom(orders.IDs()...)(ctx, value, arguments)
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseCents(t *testing.T) {
+	tests := []struct {
+		price   string
+		want    int64
+		wantErr bool
+	}{
+		{"49.99", 4999, false},
+		{"0.05", 5, false},
+		{"1200.00", 120000, false},
+		{"49", 0, true},
+		{"49.9", 0, true},
+		{"49.999", 0, true},
+		{"4x.99", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCents(tt.price)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseCents(%q) = %d, %v; want %d, error %v", tt.price, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatCents(t *testing.T) {
+	tests := []struct {
+		cents int64
+		want  string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{4999, "49.99"},
+		{120000, "1200.00"},
+	}
+	for _, tt := range tests {
+		if got := formatCents(tt.cents); got != tt.want {
+			t.Errorf("formatCents(%d) = %q, want %q", tt.cents, got, tt.want)
+		}
+	}
+}
+
+func TestNewOrder(t *testing.T) {
+	basic, _ := findProduct("price_basic_starter")
+	premium, _ := findProduct("price_premium_widget")
+	basicCents, _ := parseCents(basic.Price)
+	premiumCents, _ := parseCents(premium.Price)
+
+	order, err := newOrder("ord_test", "paid", time.Now(), map[string]int{"price_premium_widget": 1, "price_basic_starter": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := formatCents(2*basicCents + premiumCents); order.Total != want {
+		t.Errorf("Total = %s, want %s", order.Total, want)
+	}
+	if len(order.Items) != 2 || order.Items[0].PriceID != "price_basic_starter" {
+		t.Errorf("Items = %+v, want sorted by price ID", order.Items)
+	}
+
+	if _, err := newOrder("ord_test", "paid", time.Now(), map[string]int{"price_missing": 1}); err == nil {
+		t.Error("newOrder with an unknown product succeeded")
+	}
+}
+
+func TestOrderStorePutNotifies(t *testing.T) {
+	var changed []string
+	store := NewOrderStore(func(id string) { changed = append(changed, id) })
+	if len(changed) != 0 {
+		t.Errorf("loading the sample orders notified %v", changed)
+	}
+
+	order, _ := store.Get("ord_1002")
+	order.Status = "shipped"
+	store.Put(order)
+	store.Put(Order{ID: "ord_2000", Status: "pending"})
+	if want := []string{"ord_1002", "ord_2000"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("notified %v, want %v", changed, want)
+	}
+	if got, _ := store.Get("ord_1002"); got.Status != "shipped" {
+		t.Errorf("ord_1002 status = %q after Put, want shipped", got.Status)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// productDetailWidgetURI is the URI of the product detail widget.
+const productDetailWidgetURI = "ui://widget/product_detail.html"
+
+// getProductArgs are the arguments of the get_product tool.
+type getProductArgs struct {
+	PriceID string `json:"priceId" validate:"required" description:"Price ID of a product or one of its variants, as returned by list_products"`
+}
+
+// getProductOutput is the structuredContent of get_product.
+type getProductOutput struct {
+	Product  Product `json:"product" validate:"required" description:"The product with its variants, images, highlights and FAQs"`
+	Selected string  `json:"selected" validate:"required" description:"Price ID of the variant to show first"`
+}
+
+// productDetailText renders product as Markdown, marking the selected
+// variant.
+func productDetailText(product Product, selected string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🛍️ **%s** - $%s\n\n%s\n", product.Name, product.Price, product.Description)
+
+	if len(product.Variants) > 0 {
+		b.WriteString("\n**Options**\n")
+		for _, v := range product.Variants {
+			marker := ""
+			if v.PriceID == selected {
+				marker = " ← selected"
+			}
+			fmt.Fprintf(&b, "- %s, billed %s: $%s (`%s`)%s\n", v.Tier, v.BillingPeriod, v.Price, v.PriceID, marker)
+		}
+	}
+	if len(product.Highlights) > 0 {
+		b.WriteString("\n**Highlights**\n")
+		for _, h := range product.Highlights {
+			fmt.Fprintf(&b, "- %s\n", h)
+		}
+	}
+	if len(product.FAQs) > 0 {
+		b.WriteString("\n**FAQ**\n")
+		for _, faq := range product.FAQs {
+			fmt.Fprintf(&b, "- *%s* %s\n", faq.Question, faq.Answer)
+		}
+	}
+	b.WriteString("\n---\n💡 *Select an option to proceed with your order.*")
+	return b.String()
+}
+
+// registerProductDetail adds the get_product tool.
+func registerProductDetail(s *server.MCPServer) {
+	getProductTool := mcp.NewTool("get_product",
+		mcp.WithDescription("Display a product's details: variants by tier and billing period, an image gallery, highlights and FAQs"),
+		withHints(toolHints{Title: "Get Product", ReadOnly: true, Idempotent: true}),
+		withArguments[getProductArgs](),
+		withOutput[getProductOutput](),
+	)
+
+	s.AddTool(getProductTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args getProductArgs) (*mcp.CallToolResult, error) {
+		done := traceLookup(ctx, "catalog.product", attribute.String("catalog.price_id", args.PriceID))
+		product, ok := findProductOrVariant(args.PriceID)
+		done(ok)
+		if !ok {
+			argErr := &ArgumentError{}
+			argErr.add("priceId", "is not a product; products are %s", strings.Join(catalogPriceIDs(), ", "))
+			return argErr.Result(), nil
+		}
+
+		output := getProductOutput{Product: product, Selected: args.PriceID}
+		return widgetResult(ctx, productDetailWidgetURI, productDetailWidgetFile, productDetailText(product, args.PriceID), output), nil
+	}))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindProductOrVariant(t *testing.T) {
+	tests := []struct {
+		priceID string
+		product string
+		found   bool
+	}{
+		{"price_premium_widget", "price_premium_widget", true},
+		{"price_premium_widget_yearly", "price_premium_widget", true},
+		{"price_premium_widget_plus", "price_premium_widget", true},
+		{"price_standard_package_yearly", "price_standard_package", true},
+		{"price_missing", "", false},
+	}
+	for _, tt := range tests {
+		product, ok := findProductOrVariant(tt.priceID)
+		if ok != tt.found || product.PriceID != tt.product {
+			t.Errorf("findProductOrVariant(%q) = %q, %v; want %q, %v", tt.priceID, product.PriceID, ok, tt.product, tt.found)
+		}
+	}
+
+	product, variant, ok := findVariant("price_premium_widget_plus_yearly")
+	if !ok || product.PriceID != "price_premium_widget" || variant.Tier != "Premium Plus" || variant.BillingPeriod != "yearly" {
+		t.Errorf("findVariant = %q, %+v, %v", product.PriceID, variant, ok)
+	}
+}
+
+func TestProductDetailTextMarksSelected(t *testing.T) {
+	product, _ := findProduct("price_premium_widget")
+	tests := []struct {
+		selected string
+		marked   string
+	}{
+		{"price_premium_widget", "`price_premium_widget`) ← selected"},
+		{"price_premium_widget_yearly", "`price_premium_widget_yearly`) ← selected"},
+		{"price_premium_widget_plus_yearly", "`price_premium_widget_plus_yearly`) ← selected"},
+	}
+	for _, tt := range tests {
+		text := productDetailText(product, tt.selected)
+		if !strings.Contains(text, tt.marked) {
+			t.Errorf("productDetailText(%s) does not mark it:\n%s", tt.selected, text)
+		}
+		if n := strings.Count(text, "← selected"); n != 1 {
+			t.Errorf("productDetailText(%s) marks %d variants, want 1", tt.selected, n)
+		}
+	}
+}
+
+func TestGetProductSelected(t *testing.T) {
+	tests := []struct {
+		priceID  string
+		product  string
+		selected string
+	}{
+		{"price_premium_widget", "price_premium_widget", "price_premium_widget"},
+		{"price_premium_widget_yearly", "price_premium_widget", "price_premium_widget_yearly"},
+		{"price_standard_package_yearly", "price_standard_package", "price_standard_package_yearly"},
+	}
+	for _, tt := range tests {
+		response := callTool(t, registerProductDetail, "get_product", map[string]any{"priceId": tt.priceID})
+		result, _ := response["result"].(map[string]any)
+		structured, _ := result["structuredContent"].(map[string]any)
+		product, _ := structured["product"].(map[string]any)
+		if result["isError"] == true || product["priceId"] != tt.product || structured["selected"] != tt.selected {
+			t.Errorf("get_product(%s) = product %v, selected %v; want %s, %s", tt.priceID, product["priceId"], structured["selected"], tt.product, tt.selected)
+		}
+	}
+
+	response := callTool(t, registerProductDetail, "get_product", map[string]any{"priceId": "price_missing"})
+	if result, _ := response["result"].(map[string]any); result["isError"] != true {
+		t.Errorf("get_product(price_missing) = %v, want an error result", response)
+	}
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// PromptsConfig locates the prompt library.
+type PromptsConfig struct {
+	// Dir holds one .md file per prompt.
+	Dir string `json:"dir"`
+	// ReloadInterval is how often, in seconds, Dir is checked for changes.
+	// SIGHUP always forces a reload.
+	ReloadInterval int `json:"reload_interval"`
+}
+
+// A prompt file starts with YAML front-matter between "---" lines:
+//
+//	---
+//	name: code_review
+//	version: 2
+//	description: Generate a code review prompt
+//	arguments:
+//	  - name: language
+//	    description: Programming language
+//	    required: true
+//	    completions: [Go, Python]
+//	---
+//
+// The body is a sequence of messages. A line "[system]", "[user]" or
+// "[assistant]" starts a message with that role; text before the first
+// marker is a user message. "[user resource URI]" embeds the resource at URI
+// as a message of its own. Message text and URIs are text/template
+// templates over the prompt arguments ({{.language}}).
+//
+// MCP prompts have no system role, so system messages are sent as user
+// messages ahead of the others.
+
+// promptFrontMatter is the YAML header of a prompt file.
+type promptFrontMatter struct {
+	Name        string `yaml:"name"`
+	Version     int    `yaml:"version"`
+	Description string `yaml:"description"`
+	// ResultDescription describes prompts/get results; Description is used
+	// if it is empty.
+	ResultDescription string `yaml:"result_description"`
+	Arguments         []struct {
+		Name        string   `yaml:"name"`
+		Description string   `yaml:"description"`
+		Required    bool     `yaml:"required"`
+		Completions []string `yaml:"completions"`
+	} `yaml:"arguments"`
+}
+
+// promptSection is one message of a prompt body.
+type promptSection struct {
+	role     string // "system", "user" or "assistant"
+	text     *template.Template
+	resource *template.Template // URI of an embedded resource, if set
+}
+
+// promptTemplate is a parsed prompt file.
+type promptTemplate struct {
+	file     string
+	meta     promptFrontMatter
+	sections []promptSection
+}
+
+var promptSectionMarker = regexp.MustCompile(`^\[(system|user|assistant)(?:\s+resource\s+(.+?))?\]\s*$`)
+
+var promptFuncs = template.FuncMap{
+	// default returns def when value is empty: {{default "Go" .language}}.
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// parsePromptFile parses the contents of a prompt file.
+func parsePromptFile(file string, data []byte) (*promptTemplate, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, errors.New("missing front-matter")
+	}
+	header, body, ok := strings.Cut(text[len("---\n"):], "\n---\n")
+	if !ok {
+		return nil, errors.New("unterminated front-matter")
+	}
+
+	p := &promptTemplate{file: file}
+	if err := yaml.Unmarshal([]byte(header), &p.meta); err != nil {
+		return nil, fmt.Errorf("invalid front-matter: %v", err)
+	}
+	if p.meta.Name == "" {
+		return nil, errors.New("front-matter has no name")
+	}
+
+	role, resource, lines := "user", "", []string(nil)
+	flush := func() error {
+		content := strings.TrimSpace(strings.Join(lines, "\n"))
+		lines = nil
+		if content == "" && resource == "" {
+			return nil
+		}
+		section := promptSection{role: role}
+		var err error
+		if section.text, err = template.New(file).Funcs(promptFuncs).Option("missingkey=zero").Parse(content); err != nil {
+			return err
+		}
+		if resource != "" {
+			if section.resource, err = template.New(file).Funcs(promptFuncs).Option("missingkey=zero").Parse(resource); err != nil {
+				return err
+			}
+		}
+		p.sections = append(p.sections, section)
+		return nil
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if m := promptSectionMarker.FindStringSubmatch(line); m != nil {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			role, resource = m[1], m[2]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(p.sections) == 0 {
+		return nil, errors.New("prompt has no messages")
+	}
+	return p, nil
+}
+
+// prompt returns the MCP description of p.
+func (p *promptTemplate) prompt() mcp.Prompt {
+	prompt := mcp.Prompt{Name: p.meta.Name, Description: p.meta.Description}
+	for _, arg := range p.meta.Arguments {
+		prompt.Arguments = append(prompt.Arguments, mcp.PromptArgument{
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    arg.Required,
+		})
+	}
+	if p.meta.Version > 0 {
+		prompt.Meta = &mcp.Meta{AdditionalFields: map[string]any{"version": p.meta.Version}}
+	}
+	return prompt
+}
+
+// render executes the prompt for arguments, reading embedded resources
+// from s.
+func (p *promptTemplate) render(ctx context.Context, s *server.MCPServer, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	for _, arg := range p.meta.Arguments {
+		if arg.Required && arguments[arg.Name] == "" {
+			return nil, fmt.Errorf("%s argument is required", arg.Name)
+		}
+	}
+	data := make(map[string]string, len(arguments))
+	for k, v := range arguments {
+		data[k] = v
+	}
+
+	var system, messages []mcp.PromptMessage
+	for _, section := range p.sections {
+		role := mcp.RoleUser
+		if section.role == "assistant" {
+			role = mcp.RoleAssistant
+		}
+
+		var text bytes.Buffer
+		if err := section.text.Execute(&text, data); err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %v", p.meta.Name, err)
+		}
+		var batch []mcp.PromptMessage
+		// Conditionals leave blank lines behind; a section that renders
+		// to nothing sends no text.
+		if content := strings.TrimSpace(text.String()); content != "" {
+			batch = append(batch, mcp.PromptMessage{Role: role, Content: mcp.NewTextContent(content)})
+		}
+		if section.resource != nil {
+			var uri bytes.Buffer
+			if err := section.resource.Execute(&uri, data); err != nil {
+				return nil, fmt.Errorf("failed to render prompt %s: %v", p.meta.Name, err)
+			}
+			contents, err := readResource(ctx, s, uri.String())
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range contents {
+				batch = append(batch, mcp.PromptMessage{Role: role, Content: mcp.NewEmbeddedResource(c)})
+			}
+		}
+
+		if section.role == "system" {
+			system = append(system, batch...)
+		} else {
+			messages = append(messages, batch...)
+		}
+	}
+
+	description := p.meta.ResultDescription
+	if description == "" {
+		description = p.meta.Description
+	}
+	result := &mcp.GetPromptResult{Description: description, Messages: append(system, messages...)}
+	if p.meta.Version > 0 {
+		result.Meta = &mcp.Meta{AdditionalFields: map[string]any{"version": p.meta.Version}}
+	}
+	return result, nil
+}
+
+// readResource reads uri through s, so a prompt embeds exactly what
+// resources/read returns.
+func readResource(ctx context.Context, s *server.MCPServer, uri string) ([]mcp.ResourceContents, error) {
+	result, err := dispatchInternal(ctx, s, mcp.MethodResourcesRead, map[string]any{"uri": uri})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource %s: %v", uri, err)
+	}
+	contents, ok := result.(mcp.ReadResourceResult)
+	if !ok {
+		return nil, fmt.Errorf("failed to read resource %s: unexpected result", uri)
+	}
+	return contents.Contents, nil
+}
+
+// PromptLibrary serves the prompts defined in a directory and reloads them
+// when the files change.
+type PromptLibrary struct {
+	cfg       PromptsConfig
+	server    *server.MCPServer
+	completer *Completer
+
+	mu        sync.Mutex
+	signature string
+}
+
+// NewPromptLibrary creates a library serving cfg.Dir on s.
+func NewPromptLibrary(cfg PromptsConfig, s *server.MCPServer, completer *Completer) *PromptLibrary {
+	return &PromptLibrary{cfg: cfg, server: s, completer: completer}
+}
+
+// Load parses every prompt file and replaces the served prompts, which
+// notifies clients with prompts/list_changed. If any file is invalid the
+// served prompts are left unchanged.
+func (l *PromptLibrary) Load() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files, signature, err := l.scan()
+	if err != nil {
+		return err
+	}
+	// Remember what was seen even if parsing fails, so a broken file is
+	// retried on its next change instead of on every tick.
+	l.signature = signature
+
+	latest := make(map[string]*promptTemplate)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt %s: %v", file, err)
+		}
+		p, err := parsePromptFile(filepath.Base(file), data)
+		if err != nil {
+			return fmt.Errorf("failed to parse prompt %s: %v", file, err)
+		}
+		if prev, ok := latest[p.meta.Name]; ok {
+			if prev.meta.Version == p.meta.Version {
+				return fmt.Errorf("prompt %s is defined by both %s and %s with version %d", p.meta.Name, prev.file, p.file, p.meta.Version)
+			}
+			if prev.meta.Version > p.meta.Version {
+				continue
+			}
+		}
+		latest[p.meta.Name] = p
+	}
+
+	prompts := make([]server.ServerPrompt, 0, len(latest))
+	completions := make(map[string]map[string]CompletionFunc)
+	for name, p := range latest {
+		prompts = append(prompts, server.ServerPrompt{
+			Prompt: p.prompt(),
+			Handler: tracePrompt(name, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return p.render(ctx, l.server, request.Params.Arguments)
+			}),
+		})
+		completions[name] = make(map[string]CompletionFunc)
+		for _, arg := range p.meta.Arguments {
+			if len(arg.Completions) > 0 {
+				completions[name][arg.Name] = completeFrom(arg.Completions...)
+			}
+		}
+	}
+
+	l.completer.ReplacePrompts(completions)
+	l.server.SetPrompts(prompts...)
+	return nil
+}
+
+// scan lists the prompt files and returns a signature that changes whenever
+// a file is added, removed or modified. A missing directory has no files.
+func (l *PromptLibrary) scan() ([]string, string, error) {
+	files, err := filepath.Glob(filepath.Join(l.cfg.Dir, "*.md"))
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Strings(files)
+	var signature strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&signature, "%s %d %d\n", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return files, signature.String(), nil
+}
+
+// changed reports whether the directory differs from the last load.
+func (l *PromptLibrary) changed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, signature, err := l.scan()
+	return err == nil && signature != l.signature
+}
+
+// Watch reloads on SIGHUP and whenever the files change, until ctx is done.
+func (l *PromptLibrary) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	interval := seconds(l.cfg.ReloadInterval)
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			l.reloadAndLog("SIGHUP")
+		case <-ticker.C:
+			if l.changed() {
+				l.reloadAndLog("file change")
+			}
+		}
+	}
+}
+
+func (l *PromptLibrary) reloadAndLog(reason string) {
+	if err := l.Load(); err != nil {
+		log.Printf("Prompt reload on %s failed, keeping previous prompts: %v", reason, err)
+		return
+	}
+	log.Printf("Prompts reloaded from %s on %s", l.cfg.Dir, reason)
+}
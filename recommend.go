@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// recommendProductsArgs are the arguments of the recommend_products tool.
+// Explicit teamSize, budget and features take precedence over what is read
+// from needs.
+type recommendProductsArgs struct {
+	Needs    string   `json:"needs" validate:"max=2000" description:"What you need in your own words, e.g. \"a team of 12 with SSO, under $150 a month\""`
+	TeamSize *int     `json:"teamSize" validate:"min=1" description:"Number of users"`
+	Budget   *float64 `json:"budget" validate:"min=1,max=1000000" description:"Maximum price in USD per month"`
+	Features []string `json:"features" validate:"max=8" description:"Required features by key or name, e.g. sso or \"API access\""`
+}
+
+// recommendationNeeds are the needs products were scored against.
+type recommendationNeeds struct {
+	TeamSize int      `json:"teamSize,omitempty" description:"Number of users, 0 if not given"`
+	Budget   string   `json:"budget,omitempty" description:"Maximum price in USD per month, empty if not given"`
+	Features []string `json:"features,omitempty" description:"Required feature keys"`
+}
+
+// scoreReason is one rule's contribution to a product's score.
+type scoreReason struct {
+	Rule   string `json:"rule" validate:"required,enum=team_size|budget|feature" description:"The rule that applied"`
+	Points int    `json:"points" description:"Points added, negative when the need is not met"`
+	Detail string `json:"detail" validate:"required" description:"Why the rule applied"`
+}
+
+// recommendation is the score of one product.
+type recommendation struct {
+	PriceID string        `json:"priceId" validate:"required" description:"The product"`
+	Rank    int           `json:"rank" validate:"required,min=1" description:"Position in the ranking, 1 being the best fit"`
+	Score   int           `json:"score" description:"Sum of the reasons' points"`
+	Matches bool          `json:"matches" description:"Whether the product meets every need"`
+	Reasons []scoreReason `json:"reasons" description:"How the score was reached"`
+}
+
+// recommendProductsOutput is the structuredContent of recommend_products.
+// Products is ranked so the list_products widget can render it.
+type recommendProductsOutput struct {
+	Needs           recommendationNeeds `json:"needs" validate:"required" description:"The needs as understood"`
+	Products        []Product           `json:"products" validate:"required" description:"Products ranked best fit first"`
+	Recommendations []recommendation    `json:"recommendations" validate:"required" description:"Score and reasons per product, in the same order"`
+}
+
+// Points awarded by the scorer when a need is met or missed.
+const (
+	teamFitPoints     = 30
+	teamMissPoints    = -50
+	budgetFitPoints   = 20
+	budgetMissPoints  = -30
+	featureFitPoints  = 15
+	featureMissPoints = -25
+
+	// unlimitedUsers is the capacity of products without a user limit.
+	unlimitedUsers = math.MaxInt
+)
+
+var (
+	teamSizePattern = regexp.MustCompile(`(?i)\bteam of\s+(\d+)\b|\b(\d+)\s*(?:users?|people|persons?|seats?|members?|employees|developers|devs)\b`)
+	soloPattern     = regexp.MustCompile(`(?i)\b(?:just me|only me|solo|myself|single user|one user)\b`)
+	budgetPattern   = regexp.MustCompile(`(?i)\$\s*(\d+(?:\.\d{1,2})?)|\b(\d+(?:\.\d{1,2})?)\s*(?:usd|dollars|bucks)\b`)
+)
+
+// featureKeywords match the words in needs that ask for a feature.
+var featureKeywords = map[string]*regexp.Regexp{
+	"storage":         regexp.MustCompile(`(?i)\b(?:storage|disk space)\b`),
+	"support":         regexp.MustCompile(`(?i)\b(?:support|help desk)\b`),
+	"api_access":      regexp.MustCompile(`(?i)\b(?:api|integrations?)\b`),
+	"analytics":       regexp.MustCompile(`(?i)\b(?:analytics|reporting|reports|dashboards)\b`),
+	"sso":             regexp.MustCompile(`(?i)\b(?:sso|single sign[- ]on|saml)\b`),
+	"custom_features": regexp.MustCompile(`(?i)\b(?:custom features|customization|custom development)\b`),
+	"sla":             regexp.MustCompile(`(?i)\b(?:sla|uptime)\b`),
+}
+
+// parseNeeds reads the team size, budget in cents and feature keys from
+// free-form text; zero values mean the need was not mentioned.
+func parseNeeds(text string) (teamSize int, budgetCents int64, features []string) {
+	if m := teamSizePattern.FindStringSubmatch(text); m != nil {
+		teamSize, _ = strconv.Atoi(m[1] + m[2])
+	} else if soloPattern.MatchString(text) {
+		teamSize = 1
+	}
+	if m := budgetPattern.FindStringSubmatch(text); m != nil {
+		if amount, err := strconv.ParseFloat(m[1]+m[2], 64); err == nil {
+			budgetCents = int64(math.Round(amount * 100))
+		}
+	}
+	for _, feature := range catalogFeatures {
+		if pattern := featureKeywords[feature.Key]; pattern != nil && pattern.MatchString(text) {
+			features = append(features, feature.Key)
+		}
+	}
+	return teamSize, budgetCents, features
+}
+
+// lookupFeature returns the catalog feature named by key or label,
+// ignoring case.
+func lookupFeature(name string) (catalogFeature, bool) {
+	for _, feature := range catalogFeatures {
+		if strings.EqualFold(name, feature.Key) || strings.EqualFold(name, feature.Label) {
+			return feature, true
+		}
+	}
+	return catalogFeature{}, false
+}
+
+// userCapacity parses a users feature value such as "Up to 10" or
+// "Unlimited".
+func userCapacity(value string) int {
+	if strings.EqualFold(value, "Unlimited") {
+		return unlimitedUsers
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(value, "Up to "))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// pluralUsers formats n users.
+func pluralUsers(n int) string {
+	if n == 1 {
+		return "1 user"
+	}
+	return fmt.Sprintf("%d users", n)
+}
+
+// scoreProduct applies the scoring rules to p.
+func scoreProduct(p Product, teamSize int, budgetCents int64, features []string) recommendation {
+	rec := recommendation{PriceID: p.PriceID, Reasons: []scoreReason{}}
+	add := func(rule string, points int, format string, args ...any) {
+		rec.Reasons = append(rec.Reasons, scoreReason{Rule: rule, Points: points, Detail: fmt.Sprintf(format, args...)})
+		rec.Score += points
+	}
+
+	if teamSize > 0 {
+		capacity := userCapacity(p.Features["users"])
+		switch {
+		case capacity == unlimitedUsers:
+			add("team_size", teamFitPoints, "Fits a team of %d with unlimited users", teamSize)
+		case capacity >= teamSize:
+			add("team_size", teamFitPoints, "Fits a team of %d (up to %s)", teamSize, pluralUsers(capacity))
+		default:
+			add("team_size", teamMissPoints, "Allows %s, %d needed", pluralUsers(capacity), teamSize)
+		}
+	}
+	if budgetCents > 0 {
+		if cents, err := parseCents(p.Price); err == nil {
+			if cents <= budgetCents {
+				add("budget", budgetFitPoints, "$%s/month is within the $%s budget", p.Price, formatCents(budgetCents))
+			} else {
+				add("budget", budgetMissPoints, "$%s/month is $%s over budget", p.Price, formatCents(cents-budgetCents))
+			}
+		}
+	}
+	for _, key := range features {
+		feature, _ := lookupFeature(key)
+		value, ok := p.Features[key]
+		switch {
+		case ok && value == "Included":
+			add("feature", featureFitPoints, "Includes %s", feature.Label)
+		case ok:
+			add("feature", featureFitPoints, "Includes %s: %s", feature.Label, value)
+		default:
+			add("feature", featureMissPoints, "Lacks %s", feature.Label)
+		}
+	}
+
+	rec.Matches = true
+	for _, reason := range rec.Reasons {
+		if reason.Points < 0 {
+			rec.Matches = false
+		}
+	}
+	return rec
+}
+
+// recommendProducts ranks the catalog by score, cheapest first on ties.
+func recommendProducts(teamSize int, budgetCents int64, features []string) recommendProductsOutput {
+	output := recommendProductsOutput{Needs: recommendationNeeds{TeamSize: teamSize, Features: features}}
+	if budgetCents > 0 {
+		output.Needs.Budget = formatCents(budgetCents)
+	}
+
+	products := catalogSummaries()
+	recs := make([]recommendation, len(products))
+	for i, p := range products {
+		recs[i] = scoreProduct(p, teamSize, budgetCents, features)
+	}
+	order := make([]int, len(products))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := recs[order[a]], recs[order[b]]
+		if ra.Score != rb.Score {
+			return ra.Score > rb.Score
+		}
+		ca, _ := parseCents(products[order[a]].Price)
+		cb, _ := parseCents(products[order[b]].Price)
+		return ca < cb
+	})
+	for rank, i := range order {
+		recs[i].Rank = rank + 1
+		output.Products = append(output.Products, products[i])
+		output.Recommendations = append(output.Recommendations, recs[i])
+	}
+	return output
+}
+
+// recommendationText renders output as Markdown.
+func recommendationText(output recommendProductsOutput) string {
+	var b strings.Builder
+	b.WriteString("⭐ **Recommended Products**\n\n")
+
+	var needs []string
+	if output.Needs.TeamSize > 0 {
+		needs = append(needs, fmt.Sprintf("team of %d", output.Needs.TeamSize))
+	}
+	if output.Needs.Budget != "" {
+		needs = append(needs, fmt.Sprintf("up to $%s/month", output.Needs.Budget))
+	}
+	for _, key := range output.Needs.Features {
+		feature, _ := lookupFeature(key)
+		needs = append(needs, feature.Label)
+	}
+	fmt.Fprintf(&b, "*Scored against: %s*\n\n", strings.Join(needs, ", "))
+
+	for i, p := range output.Products {
+		rec := output.Recommendations[i]
+		fmt.Fprintf(&b, "**%d. %s** - $%s (score %d)\n", rec.Rank, p.Name, p.Price, rec.Score)
+		for _, reason := range rec.Reasons {
+			sign := "✅"
+			if reason.Points < 0 {
+				sign = "❌"
+			}
+			fmt.Fprintf(&b, "   %s %s (%+d)\n", sign, reason.Detail, reason.Points)
+		}
+		b.WriteString("\n")
+	}
+
+	if output.Recommendations[0].Matches {
+		fmt.Fprintf(&b, "---\n💡 *%s meets every need.*", output.Products[0].Name)
+	} else {
+		b.WriteString("---\n💡 *No product meets every need; the closest fit is listed first.*")
+	}
+	return b.String()
+}
+
+// registerRecommendations adds the recommend_products tool.
+func registerRecommendations(s *server.MCPServer) {
+	recommendTool := mcp.NewTool("recommend_products",
+		mcp.WithDescription("Rank catalog products against your needs (team size, monthly budget, required features) with a rule-based score, and display the ranked list with the reasons for each score"),
+		withHints(toolHints{Title: "Recommend Products", ReadOnly: true, Idempotent: true}),
+		withArguments[recommendProductsArgs](),
+		withOutput[recommendProductsOutput](),
+	)
+
+	s.AddTool(recommendTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args recommendProductsArgs) (*mcp.CallToolResult, error) {
+		teamSize, budgetCents, features := parseNeeds(args.Needs)
+		if args.TeamSize != nil {
+			teamSize = *args.TeamSize
+		}
+		if args.Budget != nil {
+			budgetCents = int64(math.Round(*args.Budget * 100))
+		}
+
+		argErr := &ArgumentError{}
+		if args.Features != nil {
+			features = nil
+			var names []string
+			for _, feature := range catalogFeatures {
+				if feature.Key != "users" {
+					names = append(names, feature.Key)
+				}
+			}
+			for i, name := range args.Features {
+				feature, ok := lookupFeature(strings.TrimSpace(name))
+				switch {
+				case !ok:
+					argErr.add(fmt.Sprintf("features[%d]", i), "is not a feature; features are %s", strings.Join(names, ", "))
+				case feature.Key == "users":
+					argErr.add(fmt.Sprintf("features[%d]", i), "use teamSize for the number of users")
+				case !slices.Contains(features, feature.Key):
+					features = append(features, feature.Key)
+				}
+			}
+		}
+		if teamSize == 0 && budgetCents == 0 && len(features) == 0 && len(argErr.Errors) == 0 {
+			argErr.add("needs", "names no team size, budget or feature; try e.g. \"5 users, SSO, under $100\"")
+		}
+		if len(argErr.Errors) > 0 {
+			return argErr.Result(), nil
+		}
+
+		output := recommendProducts(teamSize, budgetCents, features)
+		return widgetResult(ctx, "widget://list-products", listProductsWidgetFile, recommendationText(output), output), nil
+	}))
+}
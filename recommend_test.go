@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNeeds(t *testing.T) {
+	tests := []struct {
+		text        string
+		teamSize    int
+		budgetCents int64
+		features    []string
+	}{
+		{"a team of 12 with SSO, under $150 a month", 12, 15000, []string{"sso"}},
+		{"5 users and API access for $49.99", 5, 4999, []string{"api_access"}},
+		{"just me, 20 dollars max", 1, 2000, nil},
+		{"storage and support for 8 developers", 8, 0, []string{"storage", "support"}},
+		{"a plan for 12 months", 0, 0, nil},
+		{"need it for 100 GB of storage", 0, 0, []string{"storage"}},
+		{"for 12 months, 30 seats", 30, 0, nil},
+		{"analytics dashboards and an uptime SLA", 0, 0, []string{"analytics", "sla"}},
+		{"something nice", 0, 0, nil},
+	}
+	for _, tt := range tests {
+		teamSize, budgetCents, features := parseNeeds(tt.text)
+		if teamSize != tt.teamSize || budgetCents != tt.budgetCents || !reflect.DeepEqual(features, tt.features) {
+			t.Errorf("parseNeeds(%q) = %d, %d, %v; want %d, %d, %v", tt.text, teamSize, budgetCents, features, tt.teamSize, tt.budgetCents, tt.features)
+		}
+	}
+}
+
+func TestScoreProduct(t *testing.T) {
+	small := Product{PriceID: "small", Price: "29.99", Features: map[string]string{"users": "Up to 5", "storage": "10 GB"}}
+	big := Product{PriceID: "big", Price: "199.99", Features: map[string]string{"users": "Unlimited", "sso": "Included"}}
+	tests := []struct {
+		name        string
+		product     Product
+		teamSize    int
+		budgetCents int64
+		features    []string
+		score       int
+		matches     bool
+	}{
+		{"no needs", small, 0, 0, nil, 0, true},
+		{"fits team and budget", small, 5, 5000, nil, teamFitPoints + budgetFitPoints, true},
+		{"team too large", small, 6, 0, nil, teamMissPoints, false},
+		{"over budget", big, 0, 5000, nil, budgetMissPoints, false},
+		{"unlimited users", big, 1000, 0, nil, teamFitPoints, true},
+		{"features", small, 0, 0, []string{"storage", "sso"}, featureFitPoints + featureMissPoints, false},
+		{"included feature", big, 0, 0, []string{"sso"}, featureFitPoints, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := scoreProduct(tt.product, tt.teamSize, tt.budgetCents, tt.features)
+			if rec.Score != tt.score || rec.Matches != tt.matches {
+				t.Errorf("scoreProduct = score %d, matches %v; want %d, %v (reasons %+v)", rec.Score, rec.Matches, tt.score, tt.matches, rec.Reasons)
+			}
+		})
+	}
+}
+
+func TestUserCapacity(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"Up to 10", 10},
+		{"unlimited", unlimitedUsers},
+		{"25", 25},
+		{"", 0},
+		{"Some", 0},
+	}
+	for _, tt := range tests {
+		if got := userCapacity(tt.value); got != tt.want {
+			t.Errorf("userCapacity(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// replayTarget sends one JSON-RPC request and returns the raw response.
+type replayTarget interface {
+	Send(ctx context.Context, request []byte) ([]byte, error)
+}
+
+// inProcessTarget dispatches requests to a freshly built MCP server without
+// going through HTTP.
+type inProcessTarget struct {
+	app *mcpApp
+}
+
+func (t *inProcessTarget) Send(ctx context.Context, request []byte) ([]byte, error) {
+	response, ok := t.app.completer.HandleMessage(ctx, request)
+	if !ok {
+		// There is no session in process, so subscriptions are refused as
+		// they are by a stateless server.
+		response, ok = t.app.subscriptions.HandleMessage(ctx, "", request)
+	}
+	if !ok {
+		response = t.app.server.HandleMessage(ctx, request)
+	}
+	if response == nil {
+		return nil, nil
+	}
+	return json.Marshal(response)
+}
+
+// httpTarget posts requests to a running server, keeping the session ID
+// handed out by initialize.
+type httpTarget struct {
+	url       string
+	client    *http.Client
+	sessionID string
+}
+
+func (t *httpTarget) Send(ctx context.Context, request []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(request))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if t.sessionID != "" {
+		req.Header.Set(server.HeaderKeySessionID, t.sessionID)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if id := resp.Header.Get(server.HeaderKeySessionID); id != "" {
+		t.sessionID = id
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return lastSSEResponse(body), nil
+	}
+	return body, nil
+}
+
+// lastSSEResponse returns the data of the final SSE event, which carries the
+// JSON-RPC response after any notifications.
+func lastSSEResponse(body []byte) []byte {
+	var last []byte
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if data, ok := bytes.CutPrefix(scanner.Bytes(), []byte("data:")); ok {
+			last = bytes.TrimSpace(append([]byte(nil), data...))
+		}
+	}
+	return last
+}
+
+// replayRequestParams rebuilds the JSON-RPC params of a captured entry.
+func replayRequestParams(entry *auditEntry) any {
+	switch mcp.MCPMethod(entry.Method) {
+	case mcp.MethodToolsCall:
+		return map[string]any{"name": entry.Tool, "arguments": entry.Arguments}
+	case mcp.MethodResourcesRead, methodSubscribe, methodUnsubscribe:
+		return map[string]any{"uri": entry.Resource}
+	case mcp.MethodPromptsGet:
+		return map[string]any{"name": entry.Prompt, "arguments": entry.Arguments}
+	case methodComplete:
+		ref := map[string]any{"type": "ref/prompt", "name": entry.Prompt}
+		if entry.Resource != "" {
+			ref = map[string]any{"type": "ref/resource", "uri": entry.Resource}
+		}
+		return map[string]any{"ref": ref, "argument": entry.Arguments}
+	case mcp.MethodInitialize:
+		return map[string]any{
+			"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "mcp-replay", "version": serverVersion},
+		}
+	default:
+		return map[string]any{}
+	}
+}
+
+// replayIgnore matches dotted result paths that are expected to change
+// between runs. A "*" segment matches any key or index.
+type replayIgnore [][]string
+
+func parseReplayIgnore(spec string) replayIgnore {
+	var ignore replayIgnore
+	for _, path := range strings.Split(spec, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			ignore = append(ignore, strings.Split(path, "."))
+		}
+	}
+	return ignore
+}
+
+func (ig replayIgnore) match(path []string) bool {
+	for _, pattern := range ig {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range pattern {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// diffJSON appends a line for every path where want and got differ.
+func diffJSON(path []string, want, got any, ignore replayIgnore, diffs *[]string) {
+	if ignore.match(path) {
+		return
+	}
+
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool, len(w)+len(g))
+		for k := range w {
+			keys[k] = true
+		}
+		for k := range g {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffJSON(append(path[:len(path):len(path)], k), w[k], g[k], ignore, diffs)
+		}
+		return
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			break
+		}
+		n := max(len(w), len(g))
+		for i := 0; i < n; i++ {
+			var wi, gi any
+			if i < len(w) {
+				wi = w[i]
+			}
+			if i < len(g) {
+				gi = g[i]
+			}
+			diffJSON(append(path[:len(path):len(path)], fmt.Sprint(i)), wi, gi, ignore, diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		*diffs = append(*diffs, fmt.Sprintf("  %s: recorded %s, got %s",
+			strings.Join(path, "."), truncateJSON(want), truncateJSON(got)))
+	}
+}
+
+func truncateJSON(v any) string {
+	data, _ := json.Marshal(v)
+	if len(data) > 80 {
+		return string(data[:77]) + "..."
+	}
+	return string(data)
+}
+
+// normalizeJSON round-trips v through JSON so typed results and decoded
+// responses compare as plain maps and slices.
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// runReplay implements the "replay" subcommand and returns the exit code.
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	file := fs.String("file", "logs/audit.jsonl", "JSONL capture recorded with audit.record_responses enabled")
+	url := fs.String("url", "", "MCP endpoint of a running server; empty replays against an in-process server")
+	ignoreSpec := fs.String("ignore", "", "comma-separated result paths to skip when diffing ('*' matches any segment)")
+	methods := fs.String("methods", "", "comma-separated JSON-RPC methods to replay (default: all)")
+	timeout := fs.Duration("timeout", 30*time.Second, "per-request timeout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	// Read the whole capture up front: replaying against a server that
+	// audits into the same file would otherwise replay its own output.
+	capture, err := os.ReadFile(*file)
+	if err != nil {
+		log.Printf("Failed to read capture: %v", err)
+		return 2
+	}
+
+	var target replayTarget
+	if *url == "" {
+		app, err := newMCPServer(defaultConfig(), &server.Hooks{})
+		if err != nil {
+			log.Printf("Failed to create MCP server: %v", err)
+			return 2
+		}
+		target = &inProcessTarget{app: app}
+	} else {
+		target = &httpTarget{url: *url, client: &http.Client{Timeout: *timeout}}
+		// Establish a session first in case the target runs in stateful mode.
+		if _, err := replayOne(target, &auditEntry{Method: string(mcp.MethodInitialize)}, 0, *timeout); err != nil {
+			log.Printf("Failed to initialize %s: %v", *url, err)
+			return 2
+		}
+	}
+
+	only := map[string]bool{}
+	for _, m := range strings.Split(*methods, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			only[m] = true
+		}
+	}
+	ignore := parseReplayIgnore(*ignoreSpec)
+
+	var total, failed, skipped int
+	for i, raw := range bytes.Split(capture, []byte("\n")) {
+		line := i + 1
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		var entry auditEntry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			log.Printf("line %d: skipping unparsable entry: %v", line, err)
+			skipped++
+			continue
+		}
+		if entry.Method == "" || (len(only) > 0 && !only[entry.Method]) {
+			skipped++
+			continue
+		}
+		if entry.Outcome == outcomeSuccess || entry.Outcome == outcomeToolError {
+			if entry.Response == nil {
+				skipped++
+				continue
+			}
+		}
+
+		total++
+		diffs, err := replayEntry(target, &entry, line, *timeout, ignore)
+		label := entry.Method
+		if name := entry.Tool + entry.Resource + entry.Prompt; name != "" {
+			label += " " + name
+		}
+		switch {
+		case err != nil:
+			failed++
+			fmt.Printf("FAIL line %d %s: %v\n", line, label, err)
+		case len(diffs) > 0:
+			failed++
+			fmt.Printf("DIFF line %d %s\n%s\n", line, label, strings.Join(diffs, "\n"))
+		default:
+			fmt.Printf("ok   line %d %s\n", line, label)
+		}
+	}
+	fmt.Printf("\n%d replayed, %d mismatched, %d skipped\n", total, failed, skipped)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// replayOne sends the request rebuilt from entry and decodes the response.
+func replayOne(target replayTarget, entry *auditEntry, id int, timeout time.Duration) (*mcp.JSONRPCResponse, error) {
+	request, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"method":  entry.Method,
+		"params":  replayRequestParams(entry),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	raw, err := target.Send(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		mcp.JSONRPCResponse
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	if response.Error != nil {
+		return nil, &replayRPCError{code: response.Error.Code, message: response.Error.Message}
+	}
+	return &response.JSONRPCResponse, nil
+}
+
+type replayRPCError struct {
+	code    int
+	message string
+}
+
+func (e *replayRPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.code, e.message)
+}
+
+// replayEntry re-issues one captured request and compares the outcome.
+func replayEntry(target replayTarget, entry *auditEntry, id int, timeout time.Duration, ignore replayIgnore) ([]string, error) {
+	response, err := replayOne(target, entry, id, timeout)
+
+	var rpcErr *replayRPCError
+	if entry.Outcome == outcomeError {
+		if err == nil {
+			return []string{fmt.Sprintf("  recorded error %q, got a result", entry.Error)}, nil
+		}
+		if !errors.As(err, &rpcErr) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	want, err := normalizeJSON(entry.Response)
+	if err != nil {
+		return nil, err
+	}
+	got, err := normalizeJSON(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	diffJSON(nil, want, got, ignore, &diffs)
+	return diffs, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		got    string
+		ignore string
+		diffs  []string
+	}{
+		{
+			name: "equal",
+			want: `{"a": 1, "b": [1, 2]}`,
+			got:  `{"b": [1, 2], "a": 1}`,
+		},
+		{
+			name:  "changed value",
+			want:  `{"content": [{"text": "Result: 3"}]}`,
+			got:   `{"content": [{"text": "Result: 4"}]}`,
+			diffs: []string{`  content.0.text: recorded "Result: 3", got "Result: 4"`},
+		},
+		{
+			name:  "added and removed keys",
+			want:  `{"a": 1}`,
+			got:   `{"b": 2}`,
+			diffs: []string{"  a: recorded 1, got null", "  b: recorded null, got 2"},
+		},
+		{
+			name:  "longer array",
+			want:  `[1]`,
+			got:   `[1, 2]`,
+			diffs: []string{"  1: recorded null, got 2"},
+		},
+		{
+			name:  "type change",
+			want:  `{"a": {"b": 1}}`,
+			got:   `{"a": [1]}`,
+			diffs: []string{`  a: recorded {"b":1}, got [1]`},
+		},
+		{
+			name:   "ignored path",
+			want:   `{"serverTime": "2025-01-01", "items": [{"id": 1, "at": "x"}]}`,
+			got:    `{"serverTime": "2026-01-01", "items": [{"id": 1, "at": "y"}]}`,
+			ignore: "serverTime, items.*.at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want, got any
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.got), &got); err != nil {
+				t.Fatal(err)
+			}
+			var diffs []string
+			diffJSON(nil, want, got, parseReplayIgnore(tt.ignore), &diffs)
+			if !reflect.DeepEqual(diffs, tt.diffs) {
+				t.Errorf("diffs = %q, want %q", diffs, tt.diffs)
+			}
+		})
+	}
+}
+
+func TestReplayIgnoreMatch(t *testing.T) {
+	ignore := parseReplayIgnore("result.serverTime,result.content.*.text")
+	tests := []struct {
+		path []string
+		want bool
+	}{
+		{[]string{"result", "serverTime"}, true},
+		{[]string{"result", "content", "3", "text"}, true},
+		{[]string{"result", "content", "3"}, false},
+		{[]string{"result", "uptime"}, false},
+	}
+	for _, tt := range tests {
+		if got := ignore.match(tt.path); got != tt.want {
+			t.Errorf("match(%v) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLastSSEResponse(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"event: message\ndata: {\"id\":1}\n\n", `{"id":1}`},
+		{"data: {\"method\":\"notifications/progress\"}\n\ndata: {\"id\":2}\n\n", `{"id":2}`},
+		{"event: ping\n\n", ""},
+	}
+	for _, tt := range tests {
+		if got := string(lastSSEResponse([]byte(tt.body))); got != tt.want {
+			t.Errorf("lastSSEResponse(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
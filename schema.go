@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tools that return structuredContent declare its shape as a Go struct,
+// tagged like argument structs (see args.go). withOutput publishes the
+// derived outputSchema and validateToolOutput checks every result against
+// it, so the widgets and the schema can't drift apart silently.
+
+// withOutput sets the tool's output schema from the result struct T.
+func withOutput[T any]() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		tool.OutputSchema = mcp.ToolOutputSchema(argumentsSchema(reflect.TypeOf((*T)(nil)).Elem()))
+	}
+}
+
+// OutputSchemaError reports structuredContent that does not match the
+// tool's declared outputSchema.
+type OutputSchemaError struct {
+	Tool   string       `json:"tool"`
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *OutputSchemaError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Field + " " + fe.Message
+	}
+	return fmt.Sprintf("structuredContent of %s does not match its outputSchema: %s", e.Tool, strings.Join(parts, "; "))
+}
+
+// validateToolOutput returns tool handler middleware that validates
+// structuredContent against the tool's outputSchema. In strict mode (dev)
+// a mismatch fails the call; otherwise it is logged and the result is sent
+// unchanged.
+func validateToolOutput(strict bool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || result.IsError {
+				return result, err
+			}
+			schema := outputSchemaFor(ctx, request.Params.Name)
+			if schema == nil {
+				return result, nil
+			}
+
+			if errs := validateOutput(schema, result.StructuredContent); len(errs) > 0 {
+				schemaErr := &OutputSchemaError{Tool: request.Params.Name, Errors: errs}
+				if strict {
+					return nil, schemaErr
+				}
+				log.Printf("Warning: %v", schemaErr)
+			}
+			return result, nil
+		}
+	}
+}
+
+// outputSchemaFor returns the declared outputSchema of the named tool as a
+// generic JSON value, or nil if it has none.
+func outputSchemaFor(ctx context.Context, name string) map[string]any {
+	s := server.ServerFromContext(ctx)
+	if s == nil {
+		return nil
+	}
+	tool := s.GetTool(name)
+	if tool == nil || tool.Tool.OutputSchema.Type == "" {
+		return nil
+	}
+	var schema map[string]any
+	if err := roundTripJSON(tool.Tool.OutputSchema, &schema); err != nil {
+		return nil
+	}
+	return schema
+}
+
+// validateOutput checks structuredContent against schema. A declared schema
+// makes structuredContent mandatory.
+func validateOutput(schema map[string]any, structuredContent any) []FieldError {
+	if structuredContent == nil {
+		return []FieldError{{Field: "structuredContent", Message: "is missing"}}
+	}
+	var value any
+	if err := roundTripJSON(structuredContent, &value); err != nil {
+		return []FieldError{{Field: "structuredContent", Message: "is not valid JSON: " + err.Error()}}
+	}
+	var errs []FieldError
+	validateSchema(&errs, "structuredContent", schema, value)
+	return errs
+}
+
+func roundTripJSON(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// validateSchema checks value against the subset of JSON Schema produced by
+// argumentsSchema: type, properties, required, items, enum, pattern and the
+// numeric, length and item-count bounds.
+func validateSchema(errs *[]FieldError, path string, schema map[string]any, value any) {
+	add := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	want, _ := schema["type"].(string)
+	if want != "" && !hasJSONType(value, want) {
+		add("must be a %s", want)
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				if _, present := v[name.(string)]; !present {
+					*errs = append(*errs, FieldError{Field: path + "." + name.(string), Message: "is required"})
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := properties[name].(map[string]any); ok {
+				validateSchema(errs, path+"."+name, prop, v[name])
+			}
+		}
+	case []any:
+		checkBounds(add, schema, "minItems", "maxItems", float64(len(v)), " items")
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				validateSchema(errs, path+"["+strconv.Itoa(i)+"]", items, item)
+			}
+		}
+	case string:
+		checkBounds(add, schema, "minLength", "maxLength", float64(len([]rune(v))), " characters")
+		if enum, ok := schema["enum"].([]any); ok {
+			found := false
+			for _, allowed := range enum {
+				if allowed == v {
+					found = true
+					break
+				}
+			}
+			if !found {
+				add("must be one of %v", enum)
+			}
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				add("must match %s", pattern)
+			}
+		}
+	case float64:
+		checkBounds(add, schema, "minimum", "maximum", v, "")
+	}
+}
+
+func hasJSONType(value any, want string) bool {
+	switch want {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	default:
+		return true
+	}
+}
+
+func checkBounds(add func(string, ...any), schema map[string]any, minKey, maxKey string, size float64, unit string) {
+	if min, ok := schema[minKey].(float64); ok && size < min {
+		add("must be at least %s%s", strconv.FormatFloat(min, 'f', -1, 64), unit)
+	}
+	if max, ok := schema[maxKey].(float64); ok && size > max {
+		add("must be at most %s%s", strconv.FormatFloat(max, 'f', -1, 64), unit)
+	}
+}
+
+// toolSchemas is the published description of one tool.
+type toolSchemas struct {
+	Name         string                `json:"name"`
+	Description  string                `json:"description,omitempty"`
+	InputSchema  mcp.ToolInputSchema   `json:"inputSchema"`
+	OutputSchema *mcp.ToolOutputSchema `json:"outputSchema,omitempty"`
+}
+
+func newToolSchemas(tool mcp.Tool) toolSchemas {
+	out := toolSchemas{Name: tool.Name, Description: tool.Description, InputSchema: tool.InputSchema}
+	if tool.OutputSchema.Type != "" {
+		schema := tool.OutputSchema
+		out.OutputSchema = &schema
+	}
+	return out
+}
+
+// SchemasHandler publishes the input and output schemas of every tool so
+// widget and client authors can code against them: GET /schemas lists all
+// tools and GET /schemas/{tool} returns one.
+func SchemasHandler(s *server.MCPServer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /schemas", func(w http.ResponseWriter, r *http.Request) {
+		tools := s.ListTools()
+		names := make([]string, 0, len(tools))
+		for name := range tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		list := make([]toolSchemas, 0, len(names))
+		for _, name := range names {
+			list = append(list, newToolSchemas(tools[name].Tool))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"tools": list})
+	})
+	mux.HandleFunc("GET /schemas/{tool}", func(w http.ResponseWriter, r *http.Request) {
+		tool := s.GetTool(r.PathValue("tool"))
+		if tool == nil {
+			http.Error(w, "Unknown tool", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, newToolSchemas(tool.Tool))
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
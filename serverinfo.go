@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Resources describing this server: JSON, and plain text for simple clients.
+const (
+	serverInfoURI     = "server://info"
+	serverInfoTextURI = "server://info/text"
+)
+
+// Build metadata, injected at link time (see the Makefile):
+//
+//	go build -ldflags "-X main.gitCommit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%FT%TZ)"
+//
+// When they are empty the VCS stamp the go command embeds is used instead.
+var (
+	gitCommit string
+	buildTime string
+)
+
+// startTime is when the process started, for uptime.
+var startTime = time.Now()
+
+// buildInfo identifies the binary.
+type buildInfo struct {
+	Commit    string `json:"commit"`
+	Time      string `json:"time"`
+	GoVersion string `json:"goVersion"`
+	Modified  bool   `json:"modified,omitempty"` // built from a dirty tree
+}
+
+// currentBuild returns the build metadata from the linker flags, falling
+// back to the embedded VCS stamp.
+func currentBuild() buildInfo {
+	info := buildInfo{Commit: gitCommit, Time: buildTime, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.Time == "" {
+					info.Time = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Time == "" {
+		info.Time = "unknown"
+	}
+	return info
+}
+
+// serverInfo is the JSON of server://info.
+type serverInfo struct {
+	Name          string           `json:"name"`
+	Version       string           `json:"version"`
+	Status        string           `json:"status"` // serving or draining
+	Time          time.Time        `json:"time"`
+	StartedAt     time.Time        `json:"startedAt"`
+	Uptime        string           `json:"uptime"`
+	UptimeSeconds int64            `json:"uptimeSeconds"`
+	Build         buildInfo        `json:"build"`
+	Transport     transportInfo    `json:"transport"`
+	Capabilities  capabilitiesInfo `json:"capabilities"`
+	Runtime       runtimeInfo      `json:"runtime"`
+}
+
+type transportInfo struct {
+	Type     string `json:"type"`
+	Stateful bool   `json:"stateful"`
+	TLS      bool   `json:"tls"`
+}
+
+// capabilitiesInfo lists what is registered right now, so widgets and
+// prompts added or removed at runtime show up.
+type capabilitiesInfo struct {
+	Tools             []toolInfo             `json:"tools"`
+	Resources         []mcp.Resource         `json:"resources"`
+	ResourceTemplates []mcp.ResourceTemplate `json:"resourceTemplates"`
+	Prompts           []promptInfo           `json:"prompts"`
+}
+
+type toolInfo struct {
+	Name     string `json:"name"`
+	Title    string `json:"title,omitempty"`
+	ReadOnly bool   `json:"readOnly"`
+}
+
+type promptInfo struct {
+	Name    string `json:"name"`
+	Version int    `json:"version,omitempty"`
+}
+
+type runtimeInfo struct {
+	Goroutines     int    `json:"goroutines"`
+	GOMAXPROCS     int    `json:"gomaxprocs"`
+	HeapAllocBytes uint64 `json:"heapAllocBytes"`
+	SysBytes       uint64 `json:"sysBytes"`
+	NumGC          uint32 `json:"numGC"`
+	Sessions       int    `json:"sessions"`
+}
+
+// serverInfo gathers the current server information.
+func (a *mcpApp) serverInfo(ctx context.Context, cfg *Config) (*serverInfo, error) {
+	now := time.Now()
+	uptime := now.Sub(startTime)
+	info := &serverInfo{
+		Name:          serverName,
+		Version:       serverVersion,
+		Status:        "serving",
+		Time:          now.UTC(),
+		StartedAt:     startTime.UTC(),
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		Build:         currentBuild(),
+		Transport: transportInfo{
+			Type:     "streamable-http",
+			Stateful: cfg.Server.Stateful,
+			TLS:      cfg.TLS.Enabled(),
+		},
+	}
+	if a.isDraining() {
+		info.Status = "draining"
+	}
+
+	reg, err := a.registry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tool := range reg.Tools {
+		readOnly := tool.Annotations.ReadOnlyHint
+		info.Capabilities.Tools = append(info.Capabilities.Tools, toolInfo{
+			Name:     tool.Name,
+			Title:    tool.Annotations.Title,
+			ReadOnly: readOnly != nil && *readOnly,
+		})
+	}
+	info.Capabilities.Resources = reg.Resources
+	info.Capabilities.ResourceTemplates = reg.ResourceTemplates
+	for _, p := range reg.Prompts {
+		prompt := promptInfo{Name: p.Name}
+		if p.Meta != nil {
+			prompt.Version, _ = p.Meta.AdditionalFields["version"].(int)
+		}
+		info.Capabilities.Prompts = append(info.Capabilities.Prompts, prompt)
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	info.Runtime = runtimeInfo{
+		Goroutines:     runtime.NumGoroutine(),
+		GOMAXPROCS:     runtime.GOMAXPROCS(0),
+		HeapAllocBytes: mem.HeapAlloc,
+		SysBytes:       mem.Sys,
+		NumGC:          mem.NumGC,
+		Sessions:       a.subscriptions.Sessions(),
+	}
+	return info, nil
+}
+
+// text renders info as the plain-text variant.
+func (info *serverInfo) text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Server: %s\n", info.Name)
+	fmt.Fprintf(&b, "Version: %s (commit %s, built %s, %s)\n", info.Version, info.Build.Commit, info.Build.Time, info.Build.GoVersion)
+	fmt.Fprintf(&b, "Status: %s\n", info.Status)
+	fmt.Fprintf(&b, "Uptime: %s\n", info.Uptime)
+	mode := "stateless"
+	if info.Transport.Stateful {
+		mode = "stateful"
+	}
+	fmt.Fprintf(&b, "Transport: %s, %s\n", info.Transport.Type, mode)
+	fmt.Fprintf(&b, "Tools: %d, Resources: %d, Resource templates: %d, Prompts: %d\n",
+		len(info.Capabilities.Tools),
+		len(info.Capabilities.Resources),
+		len(info.Capabilities.ResourceTemplates),
+		len(info.Capabilities.Prompts),
+	)
+	fmt.Fprintf(&b, "Time: %s", info.Time.Format(time.RFC3339))
+	return b.String()
+}
+
+// registerServerInfo adds server://info and its plain-text variant.
+func registerServerInfo(a *mcpApp, cfg *Config) {
+	a.server.AddResource(mcp.Resource{
+		URI:         serverInfoURI,
+		Name:        "Server Information",
+		Description: "Build, uptime, transport, registered tools, resources and prompts, and runtime stats of this MCP server",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		info, err := a.serverInfo(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResource(request.Params.URI, info)
+	})
+
+	a.server.AddResource(mcp.Resource{
+		URI:         serverInfoTextURI,
+		Name:        "Server Information (text)",
+		Description: "A plain-text summary of server://info",
+		MIMEType:    "text/plain",
+	}, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		info, err := a.serverInfo(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "text/plain",
+				Text:     info.text(),
+			},
+		}, nil
+	})
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ShutdownConfig controls how the server drains on SIGINT/SIGTERM. All values
+// are in seconds.
+type ShutdownConfig struct {
+	// DrainDelay keeps serving after /readyz starts failing so load
+	// balancers stop routing new sessions here before the listener closes.
+	DrainDelay int `json:"drain_delay"`
+	// GracePeriod is how long in-flight tool calls and open streams get to
+	// finish before their contexts are cancelled.
+	GracePeriod int `json:"grace_period"`
+	// HammerPeriod is how long handlers get to return after cancellation
+	// before the remaining connections are closed.
+	HammerPeriod int `json:"hammer_period"`
+}
+
+// ShutdownCoordinator owns the base context of every request and runs the
+// shutdown sequence: stop taking new sessions, drain, cancel and finally
+// flush the registered subsystems.
+type ShutdownCoordinator struct {
+	cfg ShutdownConfig
+
+	baseCtx  context.Context
+	cancel   context.CancelFunc
+	draining atomic.Bool
+
+	mu       sync.Mutex
+	flushers []shutdownFlusher
+	onDrain  []func()
+}
+
+type shutdownFlusher struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewShutdownCoordinator creates a coordinator whose base context is live
+// until the grace period of a shutdown expires.
+func NewShutdownCoordinator(cfg ShutdownConfig) *ShutdownCoordinator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ShutdownCoordinator{cfg: cfg, baseCtx: ctx, cancel: cancel}
+}
+
+// BaseContext is used as http.Server.BaseContext so that cancelling it
+// reaches every handler, including long-lived SSE streams.
+func (c *ShutdownCoordinator) BaseContext(net.Listener) context.Context {
+	return c.baseCtx
+}
+
+// OnShutdown registers fn to run after the HTTP server has stopped. Flushers
+// run in reverse registration order, like deferred calls.
+func (c *ShutdownCoordinator) OnShutdown(name string, fn func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushers = append(c.flushers, shutdownFlusher{name: name, fn: fn})
+}
+
+// OnDrain registers fn to run when the shutdown sequence starts, once
+// Draining reports true.
+func (c *ShutdownCoordinator) OnDrain(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onDrain = append(c.onDrain, fn)
+}
+
+// Draining reports whether a shutdown has started.
+func (c *ShutdownCoordinator) Draining() bool {
+	return c.draining.Load()
+}
+
+// HealthCheck fails once draining starts so /readyz takes the instance out
+// of rotation.
+func (c *ShutdownCoordinator) HealthCheck() HealthCheck {
+	return func(ctx context.Context) error {
+		if c.Draining() {
+			return errors.New("server is shutting down")
+		}
+		return nil
+	}
+}
+
+// RejectNewSessions answers 503 to initialize requests and new SSE streams
+// while draining. Requests on existing sessions are still served.
+func (c *ShutdownCoordinator) RejectNewSessions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.Draining() && opensSession(r) {
+			w.Header().Set("Retry-After", "5")
+			w.Header().Set("Connection", "close")
+			http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// opensSession reports whether r would start a new MCP session: an
+// initialize request or a GET opening a notification stream.
+func opensSession(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet:
+		return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	case http.MethodPost:
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return false
+		}
+		var message struct {
+			Method mcp.MCPMethod `json:"method"`
+		}
+		return json.Unmarshal(body, &message) == nil && message.Method == mcp.MethodInitialize
+	default:
+		return false
+	}
+}
+
+// Shutdown runs the shutdown sequence for httpServer and blocks until it is
+// complete.
+func (c *ShutdownCoordinator) Shutdown(httpServer *http.Server) {
+	c.draining.Store(true)
+	c.mu.Lock()
+	onDrain := append([]func(){}, c.onDrain...)
+	c.mu.Unlock()
+	for _, fn := range onDrain {
+		fn()
+	}
+
+	grace := seconds(c.cfg.GracePeriod)
+
+	if c.cfg.DrainDelay > 0 {
+		log.Printf("Draining: waiting %ds before closing the listener", c.cfg.DrainDelay)
+		time.Sleep(seconds(c.cfg.DrainDelay))
+	}
+
+	// Stop accepting connections and wait for in-flight requests.
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	err := httpServer.Shutdown(ctx)
+	cancel()
+
+	if err != nil {
+		// Grace period expired: cancel handler contexts so tool calls and SSE
+		// streams return, then give them the hammer period to do so.
+		log.Printf("Grace period of %s expired, cancelling in-flight requests", grace)
+		c.cancel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), seconds(c.cfg.HammerPeriod))
+		err = httpServer.Shutdown(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("Forcing remaining connections closed: %v", err)
+			httpServer.Close()
+		}
+	}
+	c.cancel()
+
+	c.flush()
+}
+
+// logShutdown sends a warning log message to every session with an open
+// stream. Only stateful servers have such sessions, and the message is
+// informational: clients learn to go elsewhere from /readyz and the 503s
+// answered to new sessions, not from this.
+func logShutdown(mcpServer *server.MCPServer, gracePeriod int) {
+	mcpServer.SendNotificationToAllClients("notifications/message", map[string]any{
+		"level":  mcp.LoggingLevelWarning,
+		"logger": serverName,
+		"data": map[string]any{
+			"message":        "Server is shutting down",
+			"grace_period_s": gracePeriod,
+		},
+	})
+}
+
+// flush runs the registered flushers, each with the hammer period as its
+// deadline.
+func (c *ShutdownCoordinator) flush() {
+	c.mu.Lock()
+	flushers := append([]shutdownFlusher(nil), c.flushers...)
+	c.mu.Unlock()
+
+	for i := len(flushers) - 1; i >= 0; i-- {
+		f := flushers[i]
+		ctx, cancel := context.WithTimeout(context.Background(), seconds(c.cfg.HammerPeriod))
+		if err := f.fn(ctx); err != nil {
+			log.Printf("Failed to flush %s: %v", f.name, err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// resources/subscribe and resources/unsubscribe, which mcp-go parses but
+// does not dispatch.
+const (
+	methodSubscribe   mcp.MCPMethod = "resources/subscribe"
+	methodUnsubscribe mcp.MCPMethod = "resources/unsubscribe"
+)
+
+// errNoSession is returned for subscriptions outside a stateful session:
+// there is nothing to send the notifications to.
+var errNoSession = errors.New("resource subscriptions need a session; enable server.stateful and send the Mcp-Session-Id header")
+
+// Subscriptions tracks which sessions subscribed to which resource URIs and
+// sends them notifications/resources/updated when Notify is called. Like
+// the Completer it answers its methods in front of the MCP server.
+type Subscriptions struct {
+	server *server.MCPServer
+	hooks  *server.Hooks
+
+	mu       sync.Mutex
+	sessions map[string]map[string]bool // session ID -> subscribed URIs
+}
+
+// NewSubscriptions creates an empty registry. Sessions are tracked through
+// hooks, so subscriptions end with the session.
+func NewSubscriptions(s *server.MCPServer, hooks *server.Hooks) *Subscriptions {
+	sub := &Subscriptions{
+		server:   s,
+		hooks:    hooks,
+		sessions: make(map[string]map[string]bool),
+	}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		sub.mu.Lock()
+		defer sub.mu.Unlock()
+		if sub.sessions[session.SessionID()] == nil {
+			sub.sessions[session.SessionID()] = make(map[string]bool)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		sub.mu.Lock()
+		defer sub.mu.Unlock()
+		delete(sub.sessions, session.SessionID())
+	})
+	return sub
+}
+
+// HandleMessage answers message if it is a subscribe or unsubscribe
+// request from sessionID. ok is false for every other message.
+func (sub *Subscriptions) HandleMessage(ctx context.Context, sessionID string, message []byte) (response mcp.JSONRPCMessage, ok bool) {
+	request, ok := parseIntercepted(message, methodSubscribe, methodUnsubscribe)
+	if !ok {
+		return nil, false
+	}
+
+	var params mcp.SubscribeParams
+	if err := json.Unmarshal(request.Params, &params); err != nil || params.URI == "" {
+		return jsonrpcError(mcp.NewRequestId(request.ID), mcp.INVALID_PARAMS, "invalid subscription params: uri is required"), true
+	}
+
+	var hookRequest any
+	if request.Method == methodSubscribe {
+		hookRequest = &mcp.SubscribeRequest{Request: mcp.Request{Method: string(methodSubscribe)}, Params: params}
+	} else {
+		hookRequest = &mcp.UnsubscribeRequest{Request: mcp.Request{Method: string(methodUnsubscribe)}, Params: mcp.UnsubscribeParams{URI: params.URI}}
+	}
+
+	return respondWithHooks(ctx, sub.hooks, request, hookRequest, func() (any, int, error) {
+		sub.mu.Lock()
+		defer sub.mu.Unlock()
+		uris, ok := sub.sessions[sessionID]
+		if !ok {
+			return nil, mcp.INVALID_REQUEST, errNoSession
+		}
+		if request.Method == methodSubscribe {
+			uris[params.URI] = true
+		} else {
+			delete(uris, params.URI)
+		}
+		return mcp.EmptyResult{}, 0, nil
+	}), true
+}
+
+// Middleware answers subscription requests posted to /mcp.
+func (sub *Subscriptions) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := readRequestBody(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if response, ok := sub.HandleMessage(r.Context(), r.Header.Get(server.HeaderKeySessionID), body); ok {
+			writeJSONRPC(w, response)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// EndSessionOnDelete unregisters the session a DELETE /mcp terminated.
+// mcp-go's DELETE handler forgets the session without unregistering it, so
+// the unregister hooks would never run for sessions opened by initialize.
+func EndSessionOnDelete(s *server.MCPServer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if id := r.Header.Get(server.HeaderKeySessionID); id != "" && rec.status == http.StatusOK {
+			s.UnregisterSession(r.Context(), id)
+		}
+	})
+}
+
+// Notify tells every session subscribed to uri that the resource changed.
+func (sub *Subscriptions) Notify(uri string) {
+	sub.mu.Lock()
+	var sessions []string
+	for id, uris := range sub.sessions {
+		if uris[uri] {
+			sessions = append(sessions, id)
+		}
+	}
+	sub.mu.Unlock()
+
+	for _, id := range sessions {
+		err := sub.server.SendNotificationToSpecificClient(id, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		if err != nil {
+			log.Printf("Warning: failed to notify session %s that %s changed: %v", id, uri, err)
+		}
+	}
+}
+
+// Sessions returns the number of open sessions.
+func (sub *Subscriptions) Sessions() int {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return len(sub.sessions)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestEndSessionOnDelete(t *testing.T) {
+	hooks := &server.Hooks{}
+	registered, unregistered := 0, 0
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) { registered++ })
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) { unregistered++ })
+	s := server.NewMCPServer("test", "1.0.0", server.WithHooks(hooks))
+	subscriptions := NewSubscriptions(s, hooks)
+	ts := httptest.NewServer(EndSessionOnDelete(s, server.NewStreamableHTTPServer(s)))
+	defer ts.Close()
+
+	initialize := `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-06-18", "capabilities": {}, "clientInfo": {"name": "test", "version": "1"}}}`
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(initialize))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	id := resp.Header.Get(server.HeaderKeySessionID)
+	if id == "" || registered != 1 || len(subscriptions.sessions) != 1 {
+		t.Fatalf("after initialize: session %q, %d registered, %d tracked", id, registered, len(subscriptions.sessions))
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set(server.HeaderKeySessionID, id)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || unregistered != 1 || len(subscriptions.sessions) != 0 {
+		t.Errorf("after DELETE: status %d, %d unregistered, %d tracked", resp.StatusCode, unregistered, len(subscriptions.sessions))
+	}
+
+	// Deleting again is a no-op for the hooks.
+	req, _ = http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set(server.HeaderKeySessionID, id)
+	if resp, err = http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+	if unregistered != 1 {
+		t.Errorf("second DELETE ran the unregister hooks again: %d", unregistered)
+	}
+}
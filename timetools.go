@@ -0,0 +1,409 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// Embed the time zone database so results don't depend on the host.
+	_ "time/tzdata"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// getTimeArgs are the arguments of the get_time tool.
+type getTimeArgs struct {
+	Timezone string `json:"timezone" validate:"max=64" description:"IANA time zone such as Europe/Berlin or America/New_York (default UTC)"`
+	Format   string `json:"format" validate:"enum=rfc3339|rfc1123|date|time|unix|unix_millis|long|short" description:"Output format (default rfc3339); long and short are localized"`
+	Locale   string `json:"locale" validate:"enum=en|de|fr|es|it|pt|nl" description:"Language of the long and short formats (default en)"`
+}
+
+// convertTimeArgs are the arguments of the convert_time tool.
+type convertTimeArgs struct {
+	Time         string `json:"time" validate:"required,max=64" description:"Time to convert: RFC 3339 (2026-10-18T15:04:05+02:00), a local time such as 2026-10-18 15:04 read in from_timezone, or now"`
+	FromTimezone string `json:"from_timezone" validate:"max=64" description:"IANA time zone of times without an offset (default UTC)"`
+	ToTimezone   string `json:"to_timezone" validate:"required,max=64" description:"IANA time zone to convert to"`
+	Format       string `json:"format" validate:"enum=rfc3339|rfc1123|date|time|unix|unix_millis|long|short" description:"Output format (default rfc3339); long and short are localized"`
+	Locale       string `json:"locale" validate:"enum=en|de|fr|es|it|pt|nl" description:"Language of the long and short formats (default en)"`
+}
+
+// timeBetweenArgs are the arguments of the time_between tool.
+type timeBetweenArgs struct {
+	Start    string   `json:"start" validate:"required,max=64" description:"Start time or date: RFC 3339, a local time such as 2026-10-18 15:04, a date such as 2026-10-18, or now"`
+	End      string   `json:"end" validate:"required,max=64" description:"End time or date, in the same forms as start"`
+	Timezone string   `json:"timezone" validate:"max=64" description:"IANA time zone of times without an offset and of the business day calendar (default UTC)"`
+	Holidays []string `json:"holidays" validate:"max=366" description:"Dates (YYYY-MM-DD) that are not business days"`
+}
+
+// timeInfo describes one instant in one time zone.
+type timeInfo struct {
+	Time         string `json:"time" validate:"required" description:"RFC 3339 timestamp"`
+	Formatted    string `json:"formatted" validate:"required" description:"The time in the requested format and locale"`
+	Timezone     string `json:"timezone" validate:"required" description:"IANA time zone"`
+	Abbreviation string `json:"abbreviation" description:"Zone abbreviation such as CEST"`
+	UTCOffset    string `json:"utcOffset" validate:"required" description:"Offset from UTC such as +02:00"`
+	DST          bool   `json:"dst" description:"Whether daylight saving time is in effect"`
+	Weekday      string `json:"weekday" description:"Day of the week in English"`
+	Unix         int64  `json:"unix" description:"Seconds since the Unix epoch"`
+}
+
+// convertTimeOutput is the structuredContent of convert_time.
+type convertTimeOutput struct {
+	From timeInfo `json:"from" validate:"required" description:"The time as given"`
+	To   timeInfo `json:"to" validate:"required" description:"The same instant in to_timezone"`
+}
+
+// timeBetweenOutput is the structuredContent of time_between.
+type timeBetweenOutput struct {
+	Start        string `json:"start" validate:"required" description:"Start as an RFC 3339 timestamp"`
+	End          string `json:"end" validate:"required" description:"End as an RFC 3339 timestamp"`
+	Duration     string `json:"duration" validate:"required" description:"Elapsed time such as 73h30m0s, to the second; negative if end is before start"`
+	Human        string `json:"human" validate:"required" description:"Elapsed time in words"`
+	Seconds      int64  `json:"seconds" description:"Elapsed seconds"`
+	CalendarDays int    `json:"calendarDays" description:"Calendar dates from start to end in timezone"`
+	BusinessDays int    `json:"businessDays" description:"Weekdays from the start date up to but not including the end date, without holidays"`
+}
+
+// timeLocale holds the names used by the localized formats.
+type timeLocale struct {
+	weekdays [7]string  // Sunday first
+	months   [12]string // January first
+	// long and short lay out a time; see formatLocalized.
+	long, short string
+}
+
+// timeLocaleTable holds the locales of the locale argument. In the layouts,
+// {weekday}, {month}, {d}, {dd}, {m}, {mm}, {yyyy}, {H}, {HH}, {h}, {MM},
+// {ampm} and {zone} are replaced.
+var timeLocaleTable = map[string]timeLocale{
+	"en": {
+		[7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		[12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		"{weekday}, {month} {d}, {yyyy} at {h}:{MM} {ampm} {zone}",
+		"{m}/{d}/{yyyy} {h}:{MM} {ampm}",
+	},
+	"de": {
+		[7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		[12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		"{weekday}, {d}. {month} {yyyy}, {HH}:{MM} {zone}",
+		"{dd}.{mm}.{yyyy} {HH}:{MM}",
+	},
+	"fr": {
+		[7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		[12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		"{weekday} {d} {month} {yyyy} à {HH}:{MM} {zone}",
+		"{dd}/{mm}/{yyyy} {HH}:{MM}",
+	},
+	"es": {
+		[7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		[12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		"{weekday}, {d} de {month} de {yyyy}, {H}:{MM} {zone}",
+		"{dd}/{mm}/{yyyy} {H}:{MM}",
+	},
+	"it": {
+		[7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		[12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		"{weekday} {d} {month} {yyyy}, {HH}:{MM} {zone}",
+		"{dd}/{mm}/{yyyy} {HH}:{MM}",
+	},
+	"pt": {
+		[7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		[12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		"{weekday}, {d} de {month} de {yyyy}, {HH}:{MM} {zone}",
+		"{dd}/{mm}/{yyyy} {HH}:{MM}",
+	},
+	"nl": {
+		[7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		[12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		"{weekday} {d} {month} {yyyy} om {HH}:{MM} {zone}",
+		"{dd}-{mm}-{yyyy} {HH}:{MM}",
+	},
+}
+
+// formatLocalized lays out t with the long or short layout of locale.
+func formatLocalized(t time.Time, layout string, locale timeLocale) string {
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	ampm := "AM"
+	if t.Hour() >= 12 {
+		ampm = "PM"
+	}
+	zone, _ := t.Zone()
+	return strings.NewReplacer(
+		"{weekday}", locale.weekdays[t.Weekday()],
+		"{month}", locale.months[t.Month()-1],
+		"{d}", strconv.Itoa(t.Day()),
+		"{dd}", fmt.Sprintf("%02d", t.Day()),
+		"{m}", strconv.Itoa(int(t.Month())),
+		"{mm}", fmt.Sprintf("%02d", int(t.Month())),
+		"{yyyy}", strconv.Itoa(t.Year()),
+		"{H}", strconv.Itoa(t.Hour()),
+		"{HH}", fmt.Sprintf("%02d", t.Hour()),
+		"{h}", strconv.Itoa(hour12),
+		"{MM}", fmt.Sprintf("%02d", t.Minute()),
+		"{ampm}", ampm,
+		"{zone}", zone,
+	).Replace(layout)
+}
+
+// formatTime renders t in one of timeFormats.
+func formatTime(t time.Time, format, locale string) string {
+	if locale == "" {
+		locale = "en"
+	}
+	switch format {
+	case "rfc1123":
+		return t.Format(time.RFC1123Z)
+	case "date":
+		return t.Format(time.DateOnly)
+	case "time":
+		return t.Format(time.TimeOnly)
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unix_millis":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case "long":
+		return formatLocalized(t, timeLocaleTable[locale].long, timeLocaleTable[locale])
+	case "short":
+		return formatLocalized(t, timeLocaleTable[locale].short, timeLocaleTable[locale])
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+// newTimeInfo describes t, which is already in its time zone. Times given
+// with only an offset are described as UTC+hh:mm.
+func newTimeInfo(t time.Time, format, locale string) timeInfo {
+	zone, _ := t.Zone()
+	name := t.Location().String()
+	if name == "" {
+		name = "UTC" + t.Format("-07:00")
+	}
+	if zone == "" {
+		zone = name
+	}
+	return timeInfo{
+		Time:         t.Format(time.RFC3339),
+		Formatted:    formatTime(t, format, locale),
+		Timezone:     name,
+		Abbreviation: zone,
+		UTCOffset:    t.Format("-07:00"),
+		DST:          t.IsDST(),
+		Weekday:      t.Weekday().String(),
+		Unix:         t.Unix(),
+	}
+}
+
+// loadTimezone loads an IANA time zone, defaulting to UTC. The host's
+// Local zone is refused so results are the same on every server.
+func loadTimezone(argErr *ArgumentError, field, name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		argErr.add(field, "must be an IANA time zone such as Europe/Berlin, got %q", name)
+		return time.UTC
+	}
+	return loc
+}
+
+// timeLayouts are the forms accepted for times without an offset.
+var timeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// parseTime reads a time argument: now, RFC 3339, or a local time or date
+// in loc.
+func parseTime(argErr *ArgumentError, field, value string, loc *time.Location, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "now") {
+		return now.In(loc)
+	}
+	// time.Parse would use the host's Local zone when the offset matches it.
+	if t, err := time.ParseInLocation(time.RFC3339, value, time.UTC); err == nil {
+		return t
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t
+		}
+	}
+	argErr.add(field, "must be a time such as 2026-10-18T15:04:05+02:00, 2026-10-18 15:04 or 2026-10-18, or now")
+	return time.Time{}
+}
+
+// dateOf returns the calendar date of t as midnight UTC, so dates can be
+// subtracted without daylight saving time getting in the way.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween returns the number of days from start to end, both dates
+// from dateOf. Unlike time.Sub it doesn't saturate after 292 years.
+func daysBetween(start, end time.Time) int {
+	return int((end.Unix() - start.Unix()) / (24 * 60 * 60))
+}
+
+// businessDays counts the weekdays from start up to but not including end,
+// both dates from dateOf, skipping holidays. It is negative if end is
+// before start.
+func businessDays(start, end time.Time, holidays map[time.Time]bool) int {
+	if end.Before(start) {
+		return -businessDays(end, start, holidays)
+	}
+	days := daysBetween(start, end)
+	count := days / 7 * 5
+	for d := start.AddDate(0, 0, days/7*7); d.Before(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			count++
+		}
+	}
+	for day := range holidays {
+		if !day.Before(start) && day.Before(end) && day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			count--
+		}
+	}
+	return count
+}
+
+// durationString writes seconds like time.Duration.String, e.g.
+// 73h30m0s, without its 292 year limit.
+func durationString(seconds int64) string {
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%dh%dm%ds", sign, seconds/3600, seconds/60%60, seconds%60)
+}
+
+// humanDuration writes seconds in words, e.g. "3 days, 4 hours and 5
+// minutes".
+func humanDuration(seconds int64) string {
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "minus ", -seconds
+	}
+	var parts []string
+	for _, unit := range []struct {
+		name string
+		size int64
+	}{
+		{"day", 24 * 60 * 60},
+		{"hour", 60 * 60},
+		{"minute", 60},
+		{"second", 1},
+	} {
+		n := seconds / unit.size
+		seconds -= n * unit.size
+		switch {
+		case n == 1:
+			parts = append(parts, "1 "+unit.name)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, unit.name))
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return "0 seconds"
+	case 1:
+		return sign + parts[0]
+	default:
+		return sign + strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+	}
+}
+
+// registerTimeTools adds get_time, convert_time and time_between.
+func registerTimeTools(s *server.MCPServer) {
+	timeTool := mcp.NewTool("get_time",
+		mcp.WithDescription("Returns the current time in a time zone, format and locale"),
+		withHints(toolHints{Title: "Current Time", ReadOnly: true}),
+		withArguments[getTimeArgs](),
+		withOutput[timeInfo](),
+	)
+
+	s.AddTool(timeTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args getTimeArgs) (*mcp.CallToolResult, error) {
+		argErr := &ArgumentError{}
+		loc := loadTimezone(argErr, "timezone", args.Timezone)
+		if len(argErr.Errors) > 0 {
+			return argErr.Result(), nil
+		}
+		info := newTimeInfo(time.Now().In(loc), args.Format, args.Locale)
+		text := fmt.Sprintf("Current time: %s (%s, UTC%s)", info.Formatted, info.Timezone, info.UTCOffset)
+		return mcp.NewToolResultStructured(info, text), nil
+	}))
+
+	convertTool := mcp.NewTool("convert_time",
+		mcp.WithDescription("Converts a time from one time zone to another"),
+		withHints(toolHints{Title: "Convert Time", ReadOnly: true, Idempotent: true}),
+		withArguments[convertTimeArgs](),
+		withOutput[convertTimeOutput](),
+	)
+
+	s.AddTool(convertTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args convertTimeArgs) (*mcp.CallToolResult, error) {
+		argErr := &ArgumentError{}
+		from := loadTimezone(argErr, "from_timezone", args.FromTimezone)
+		to := loadTimezone(argErr, "to_timezone", args.ToTimezone)
+		t := parseTime(argErr, "time", args.Time, from, time.Now())
+		if len(argErr.Errors) > 0 {
+			return argErr.Result(), nil
+		}
+		output := convertTimeOutput{
+			From: newTimeInfo(t, args.Format, args.Locale),
+			To:   newTimeInfo(t.In(to), args.Format, args.Locale),
+		}
+		text := fmt.Sprintf("%s (%s) is %s (%s)",
+			output.From.Formatted, output.From.Timezone, output.To.Formatted, output.To.Timezone)
+		return mcp.NewToolResultStructured(output, text), nil
+	}))
+
+	betweenTool := mcp.NewTool("time_between",
+		mcp.WithDescription("Computes the duration, calendar days and business days between two times"),
+		withHints(toolHints{Title: "Time Between", ReadOnly: true, Idempotent: true}),
+		withArguments[timeBetweenArgs](),
+		withOutput[timeBetweenOutput](),
+	)
+
+	s.AddTool(betweenTool, typedToolHandler(func(ctx context.Context, request mcp.CallToolRequest, args timeBetweenArgs) (*mcp.CallToolResult, error) {
+		argErr := &ArgumentError{}
+		loc := loadTimezone(argErr, "timezone", args.Timezone)
+		now := time.Now()
+		start := parseTime(argErr, "start", args.Start, loc, now)
+		end := parseTime(argErr, "end", args.End, loc, now)
+		holidays := make(map[time.Time]bool, len(args.Holidays))
+		for _, h := range args.Holidays {
+			day, err := time.Parse(time.DateOnly, h)
+			if err != nil {
+				argErr.add("holidays", "must be dates such as 2026-12-25, got %q", h)
+				continue
+			}
+			holidays[day] = true
+		}
+		if len(argErr.Errors) > 0 {
+			return argErr.Result(), nil
+		}
+
+		seconds := end.Unix() - start.Unix()
+		startDate, endDate := dateOf(start.In(loc)), dateOf(end.In(loc))
+		output := timeBetweenOutput{
+			Start:        start.Format(time.RFC3339),
+			End:          end.Format(time.RFC3339),
+			Duration:     durationString(seconds),
+			Human:        humanDuration(seconds),
+			Seconds:      seconds,
+			CalendarDays: daysBetween(startDate, endDate),
+			BusinessDays: businessDays(startDate, endDate, holidays),
+		}
+		text := fmt.Sprintf("From %s to %s: %s; calendar days: %d, business days: %d",
+			output.Start, output.End, output.Human, output.CalendarDays, output.BusinessDays)
+		return mcp.NewToolResultStructured(output, text), nil
+	}))
+}
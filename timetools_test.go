@@ -0,0 +1,164 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		invalid bool
+	}{
+		{"", "UTC", false},
+		{"Europe/Berlin", "Europe/Berlin", false},
+		{"America/New_York", "America/New_York", false},
+		{"Local", "UTC", true},
+		{"Mars/Olympus", "UTC", true},
+	}
+	for _, tt := range tests {
+		argErr := &ArgumentError{}
+		loc := loadTimezone(argErr, "timezone", tt.name)
+		if loc.String() != tt.want || (len(argErr.Errors) > 0) != tt.invalid {
+			t.Errorf("loadTimezone(%q) = %s, errors %v; want %s, invalid %v", tt.name, loc, argErr.Errors, tt.want, tt.invalid)
+		}
+	}
+}
+
+func TestParseTimeConversion(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		loc   *time.Location
+		to    *time.Location
+		want  string
+	}{
+		{"2026-07-01T12:00:00Z", time.UTC, berlin, "2026-07-01T14:00:00+02:00"},
+		{"2026-01-15 09:30", berlin, tokyo, "2026-01-15T17:30:00+09:00"},
+		{"2026-03-29", berlin, time.UTC, "2026-03-28T23:00:00Z"},
+		{"2026-10-25T02:30:00+01:00", time.UTC, berlin, "2026-10-25T02:30:00+01:00"},
+		{"now", berlin, tokyo, "2026-10-18T21:00:00+09:00"},
+	}
+	for _, tt := range tests {
+		argErr := &ArgumentError{}
+		got := parseTime(argErr, "time", tt.value, tt.loc, now)
+		if len(argErr.Errors) > 0 {
+			t.Errorf("parseTime(%q): %v", tt.value, argErr)
+			continue
+		}
+		if s := got.In(tt.to).Format(time.RFC3339); s != tt.want {
+			t.Errorf("parseTime(%q) in %s = %s, want %s", tt.value, tt.to, s, tt.want)
+		}
+	}
+
+	argErr := &ArgumentError{}
+	parseTime(argErr, "time", "tomorrow", time.UTC, now)
+	if len(argErr.Errors) != 1 {
+		t.Errorf("parseTime(%q) errors = %v, want one", "tomorrow", argErr.Errors)
+	}
+}
+
+func TestParseTimeIgnoresHostZone(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	local := time.Local
+	defer func() { time.Local = local }()
+
+	tests := []struct {
+		value    string
+		timezone string
+	}{
+		{"2026-10-18T15:04:05+02:00", "UTC+02:00"},
+		{"2026-10-18T15:04:05Z", "UTC"},
+		{"2026-10-18T15:04:05+00:00", "UTC"},
+	}
+	for _, host := range []*time.Location{time.UTC, berlin} {
+		time.Local = host
+		for _, tt := range tests {
+			argErr := &ArgumentError{}
+			info := newTimeInfo(parseTime(argErr, "time", tt.value, time.UTC, time.Now()), "rfc3339", "")
+			if info.Timezone != tt.timezone || info.Abbreviation != tt.timezone {
+				t.Errorf("on a %s host, parseTime(%q) is in %s (%s), want %s", host, tt.value, info.Timezone, info.Abbreviation, tt.timezone)
+			}
+		}
+	}
+}
+
+func TestNewTimeInfo(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	summer := time.Date(2026, 7, 1, 14, 5, 0, 0, berlin)
+	tests := []struct {
+		t         time.Time
+		format    string
+		locale    string
+		timezone  string
+		formatted string
+		dst       bool
+	}{
+		{summer, "long", "en", "Europe/Berlin", "Wednesday, July 1, 2026 at 2:05 PM CEST", true},
+		{summer, "long", "de", "Europe/Berlin", "Mittwoch, 1. Juli 2026, 14:05 CEST", true},
+		{summer, "short", "fr", "Europe/Berlin", "01/07/2026 14:05", true},
+		{summer.In(time.UTC), "date", "", "UTC", "2026-07-01", false},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.FixedZone("", 5*3600+1800)), "rfc3339", "", "UTC+05:30", "2026-01-01T00:00:00+05:30", false},
+	}
+	for _, tt := range tests {
+		info := newTimeInfo(tt.t, tt.format, tt.locale)
+		if info.Timezone != tt.timezone || info.Formatted != tt.formatted || info.DST != tt.dst {
+			t.Errorf("newTimeInfo(%s, %s, %s) = %+v", tt.t, tt.format, tt.locale, info)
+		}
+	}
+}
+
+func TestDaysBetween(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	holidays := map[time.Time]bool{date("2026-12-25"): true, date("2026-12-26"): true}
+	tests := []struct {
+		start, end   string
+		days         int
+		businessDays int
+	}{
+		{"2026-10-19", "2026-10-19", 0, 0},
+		{"2026-10-19", "2026-10-26", 7, 5},
+		{"2026-10-17", "2026-10-19", 2, 0},
+		{"2026-10-23", "2026-10-27", 4, 2},
+		{"2026-10-26", "2026-10-19", -7, -5},
+		{"2026-12-21", "2026-12-28", 7, 4},
+		{"2026-03-28", "2026-03-30", 2, 0},
+		{"2000-01-01", "2400-01-01", 146097, 104354},
+	}
+	for _, tt := range tests {
+		start, end := date(tt.start), date(tt.end)
+		if got := daysBetween(start, end); got != tt.days {
+			t.Errorf("daysBetween(%s, %s) = %d, want %d", tt.start, tt.end, got, tt.days)
+		}
+		if got := businessDays(start, end, holidays); got != tt.businessDays {
+			t.Errorf("businessDays(%s, %s) = %d, want %d", tt.start, tt.end, got, tt.businessDays)
+		}
+	}
+}
+
+func TestDurations(t *testing.T) {
+	tests := []struct {
+		seconds int64
+		short   string
+		human   string
+	}{
+		{0, "0h0m0s", "0 seconds"},
+		{61, "0h1m1s", "1 minute and 1 second"},
+		{3*86400 + 4*3600 + 5*60, "76h5m0s", "3 days, 4 hours and 5 minutes"},
+		{-7200, "-2h0m0s", "minus 2 hours"},
+	}
+	for _, tt := range tests {
+		if got := durationString(tt.seconds); got != tt.short {
+			t.Errorf("durationString(%d) = %q, want %q", tt.seconds, got, tt.short)
+		}
+		if got := humanDuration(tt.seconds); got != tt.human {
+			t.Errorf("humanDuration(%d) = %q, want %q", tt.seconds, got, tt.human)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// TLSConfig enables HTTPS serving. Leaving CertFile empty serves plain HTTP.
+type TLSConfig struct {
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	// MinVersion is "1.2" or "1.3".
+	MinVersion string `json:"min_version"`
+	// ClientCAFile is a PEM bundle used to verify client certificates.
+	ClientCAFile string `json:"client_ca_file"`
+	// ClientAuth is "none", "request", "verify_if_given" or "require".
+	// "require" rejects callers without a certificate signed by ClientCAFile.
+	ClientAuth string `json:"client_auth"`
+	// ReloadInterval is how often, in seconds, the files are checked for
+	// changes. SIGHUP always forces a reload.
+	ReloadInterval int `json:"reload_interval"`
+}
+
+// Enabled reports whether the server should listen with TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+func (c TLSConfig) minVersion() (uint16, error) {
+	switch c.MinVersion {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS min_version %q", c.MinVersion)
+	}
+}
+
+func (c TLSConfig) clientAuth() (tls.ClientAuthType, error) {
+	switch c.ClientAuth {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS client_auth %q", c.ClientAuth)
+	}
+}
+
+// CertReloader serves the current certificate and client CA pool and swaps
+// them when the files change or the process receives SIGHUP, so rotating a
+// certificate never needs a restart.
+type CertReloader struct {
+	cfg        TLSConfig
+	minVersion uint16
+	clientAuth tls.ClientAuthType
+
+	cert      atomic.Pointer[tls.Certificate]
+	clientCAs atomic.Pointer[x509.CertPool]
+
+	mu       sync.Mutex
+	modTimes map[string]time.Time
+}
+
+// NewCertReloader validates cfg and loads the initial certificate.
+func NewCertReloader(cfg TLSConfig) (*CertReloader, error) {
+	if cfg.KeyFile == "" {
+		return nil, fmt.Errorf("tls.key_file is required when tls.cert_file is set")
+	}
+	minVersion, err := cfg.minVersion()
+	if err != nil {
+		return nil, err
+	}
+	clientAuth, err := cfg.clientAuth()
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth >= tls.VerifyClientCertIfGiven && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("tls.client_ca_file is required for client_auth %q", cfg.ClientAuth)
+	}
+
+	r := &CertReloader{
+		cfg:        cfg,
+		minVersion: minVersion,
+		clientAuth: clientAuth,
+		modTimes:   make(map[string]time.Time),
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the certificate, key and client CA files again. On error the
+// previously loaded material stays in use.
+func (r *CertReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Remember what was seen even if loading fails, so a broken file is
+	// retried on its next change instead of on every tick.
+	for _, path := range r.files() {
+		if info, err := os.Stat(path); err == nil {
+			r.modTimes[path] = info.ModTime()
+		}
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+
+	var pool *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %v", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client CA file %s", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.cert.Store(&cert)
+	r.clientCAs.Store(pool)
+	return nil
+}
+
+func (r *CertReloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+// changed reports whether any watched file has a new modification time.
+func (r *CertReloader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, path := range r.files() {
+		info, err := os.Stat(path)
+		if err != nil {
+			// Mid-rotation the file may briefly be missing; try next tick.
+			continue
+		}
+		if !info.ModTime().Equal(r.modTimes[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+// TLSConfig returns a server config that always hands out the latest
+// certificate and client CA pool. With client_auth "require" the handshake
+// only verifies certificates that are given, so probes and scrapers can
+// still reach /livez, /readyz and /metrics; RequireClientCert enforces the
+// certificate on /mcp.
+func (r *CertReloader) TLSConfig() *tls.Config {
+	clientAuth := r.clientAuth
+	if clientAuth == tls.RequireAndVerifyClientCert {
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
+	base := &tls.Config{
+		MinVersion: r.minVersion,
+		ClientAuth: clientAuth,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.cert.Load(), nil
+		},
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		cfg := base.Clone()
+		cfg.GetConfigForClient = nil
+		cfg.ClientCAs = r.clientCAs.Load()
+		return cfg, nil
+	}
+	return base
+}
+
+// RequireClientCert answers 401 to requests without a verified client
+// certificate.
+func RequireClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Watch reloads on SIGHUP and whenever the files change, until ctx is done.
+func (r *CertReloader) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	interval := seconds(r.cfg.ReloadInterval)
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reloadAndLog("SIGHUP")
+		case <-ticker.C:
+			if r.changed() {
+				r.reloadAndLog("file change")
+			}
+		}
+	}
+}
+
+func (r *CertReloader) reloadAndLog(reason string) {
+	if err := r.Reload(); err != nil {
+		log.Printf("TLS reload on %s failed, keeping previous certificate: %v", reason, err)
+		return
+	}
+	log.Printf("TLS certificate reloaded on %s", reason)
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingConfig controls OpenTelemetry tracing.
+type TracingConfig struct {
+	Enabled bool `json:"enabled"`
+	// Exporter is "otlp" (OTLP over HTTP) or "stdout".
+	Exporter string `json:"exporter"`
+	// Endpoint is the OTLP collector host:port, e.g. "localhost:4318". When
+	// empty the standard OTEL_EXPORTER_OTLP_* environment variables apply.
+	Endpoint    string  `json:"endpoint"`
+	Insecure    bool    `json:"insecure"`
+	SampleRatio float64 `json:"sample_ratio"`
+}
+
+const tracerName = "github.com/razorpay/chatgptApp"
+
+// tracer is the global tracer; it is a no-op until setupTracing installs a
+// real provider.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// setupTracing installs the global tracer provider and W3C trace context
+// propagator. The returned function flushes pending spans.
+func setupTracing(ctx context.Context, cfg TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "", "otlp":
+		opts := []otlptracehttp.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %v", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serverName),
+		semconv.ServiceVersion(serverVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %v", err)
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// TraceHTTP wraps the whole mux so every HTTP request gets a server span
+// continuing any incoming traceparent header.
+func TraceHTTP(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+}
+
+// dispatchSpan carries the JSON-RPC dispatch span from the BeforeAny hook to
+// the handler middleware and the completion hooks, which cannot pass a new
+// context to each other.
+type dispatchSpan struct {
+	mu   sync.Mutex
+	span trace.Span
+}
+
+type dispatchSpanKey struct{}
+
+// withDispatchSpan reserves a slot for the dispatch span in ctx. Tracing is
+// attached per /mcp request, before the streamable server parses the body.
+func withDispatchSpan(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), dispatchSpanKey{}, &dispatchSpan{})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// spanContext returns ctx with the dispatch span, if any, as the active span
+// so handler spans become its children.
+func spanContext(ctx context.Context) context.Context {
+	holder, _ := ctx.Value(dispatchSpanKey{}).(*dispatchSpan)
+	if holder == nil {
+		return ctx
+	}
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	if holder.span == nil {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, holder.span)
+}
+
+// RegisterTracingHooks starts a span per JSON-RPC request when it is
+// dispatched and ends it with the outcome.
+func RegisterTracingHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		holder, _ := ctx.Value(dispatchSpanKey{}).(*dispatchSpan)
+		if holder == nil || isInternalRequest(ctx) {
+			return
+		}
+
+		name := string(method)
+		attrs := []attribute.KeyValue{
+			attribute.String("mcp.method.name", string(method)),
+			attribute.String("jsonrpc.request.id", fmt.Sprint(id)),
+		}
+		if target := metricsTarget(ctx, message); target != "" {
+			name += " " + target
+			attrs = append(attrs, attribute.String("mcp.target", target))
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil && session.SessionID() != "" {
+			attrs = append(attrs, attribute.String("mcp.session.id", session.SessionID()))
+		}
+
+		_, span := tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+		holder.mu.Lock()
+		holder.span = span
+		holder.mu.Unlock()
+	})
+
+	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
+		endDispatchSpan(ctx, func(span trace.Span) {
+			if res, ok := result.(*mcp.CallToolResult); ok && res.IsError {
+				span.SetAttributes(attribute.Bool("mcp.tool.is_error", true))
+				span.SetStatus(codes.Error, toolErrorText(res))
+			}
+		})
+	})
+
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		endDispatchSpan(ctx, func(span trace.Span) {
+			span.RecordError(err)
+			span.SetAttributes(attribute.String("error.type", errorType(err)))
+			span.SetStatus(codes.Error, err.Error())
+		})
+	})
+}
+
+func endDispatchSpan(ctx context.Context, annotate func(trace.Span)) {
+	holder, _ := ctx.Value(dispatchSpanKey{}).(*dispatchSpan)
+	if holder == nil || isInternalRequest(ctx) {
+		return
+	}
+	holder.mu.Lock()
+	span := holder.span
+	holder.span = nil
+	holder.mu.Unlock()
+	if span == nil {
+		return
+	}
+	annotate(span)
+	span.End()
+}
+
+// startHandlerSpan starts a handler span under the dispatch span. The
+// returned context no longer refers to the dispatch span, so downstream spans
+// started from it nest under the handler span instead.
+func startHandlerSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx, span := tracer().Start(spanContext(ctx), name, trace.WithAttributes(attrs...))
+	return context.WithValue(ctx, dispatchSpanKey{}, (*dispatchSpan)(nil)), span
+}
+
+// traceToolHandler is tool handler middleware that gives each tool call its
+// own span under the dispatch span.
+func traceToolHandler(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, span := startHandlerSpan(ctx, "tool "+request.Params.Name,
+			attribute.String("mcp.tool.name", request.Params.Name))
+		defer span.End()
+
+		result, err := next(ctx, request)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else if result != nil && result.IsError {
+			span.SetStatus(codes.Error, toolErrorText(result))
+		}
+		return result, err
+	}
+}
+
+// traceResourceHandler is resource handler middleware that gives each read
+// its own span under the dispatch span. The span is named after the
+// resource's metrics label, so client URIs only appear as an attribute.
+func traceResourceHandler(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		ctx, span := startHandlerSpan(ctx, "resource "+metricsResource(request.Params.URI),
+			attribute.String("mcp.resource.uri", request.Params.URI))
+		defer span.End()
+
+		contents, err := next(ctx, request)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return contents, err
+	}
+}
+
+// tracePrompt wraps a prompt handler in a span; mcp-go has no prompt
+// middleware so registerPrompts applies it to each handler.
+func tracePrompt(name string, next server.PromptHandlerFunc) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ctx, span := startHandlerSpan(ctx, "prompt "+name,
+			attribute.String("mcp.prompt.name", name))
+		defer span.End()
+
+		result, err := next(ctx, request)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return result, err
+	}
+}
+
+// traceLookup starts a span for a catalog or order store lookup and returns
+// the function that ends it, recording whether anything was found.
+func traceLookup(ctx context.Context, name string, attrs ...attribute.KeyValue) func(found bool) {
+	_, span := startSpan(ctx, name, attrs...)
+	return func(found bool) {
+		span.SetAttributes(attribute.Bool("lookup.found", found))
+		span.End()
+	}
+}
+
+// startSpan starts a span for a downstream call (catalog lookups, widget
+// rendering, payment providers) made while handling a request.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(spanContext(ctx), name, trace.WithAttributes(attrs...))
+}
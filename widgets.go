@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// WidgetsConfig controls how widget files are watched.
+type WidgetsConfig struct {
+	// WatchInterval is how often, in seconds, the widget files are checked
+	// for changes.
+	WatchInterval int `json:"watch_interval"`
+}
+
+// widget is an HTML widget served as a resource from a file in ui/.
+type widget struct {
+	resource mcp.Resource
+	file     string
+}
+
+// widgets are the widget resources, registered while their file exists.
+var widgets = []widget{
+	{
+		resource: mcp.Resource{
+			URI:         "widget://list-products",
+			Name:        "Product Selection Widget",
+			Description: "Interactive HTML widget for selecting products",
+			MIMEType:    "text/html+skybridge",
+		},
+		file: listProductsWidgetFile,
+	},
+	{
+		resource: mcp.Resource{
+			URI:         "ui://widget/generate_asset.html",
+			Name:        "Asset Generation Widget",
+			Description: "Interactive HTML widget for displaying generated assets (Figma-style)",
+			MIMEType:    "text/html+skybridge",
+		},
+		file: generateAssetWidgetFile,
+	},
+	{
+		resource: mcp.Resource{
+			URI:         compareProductsWidgetURI,
+			Name:        "Product Comparison Widget",
+			Description: "Interactive HTML widget showing a side-by-side product feature matrix",
+			MIMEType:    "text/html+skybridge",
+		},
+		file: compareProductsWidgetFile,
+	},
+	{
+		resource: mcp.Resource{
+			URI:         productDetailWidgetURI,
+			Name:        "Product Detail Widget",
+			Description: "Interactive HTML widget showing a product's variants, image gallery, highlights and FAQs",
+			MIMEType:    "text/html+skybridge",
+		},
+		file: productDetailWidgetFile,
+	},
+}
+
+// widgetHandler reads w's file on every request, so edits show up without
+// a restart.
+func widgetHandler(w widget) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		htmlContent, err := readWidgetHTML(ctx, w.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read widget %s: %v", w.file, err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: w.resource.MIMEType,
+				Text:     string(htmlContent),
+				Meta:     widgetMeta(),
+			},
+		}, nil
+	}
+}
+
+// widgetMeta is the metadata of widget HTML: a CSP (Content Security
+// Policy) that allows the product and asset images to load.
+func widgetMeta() map[string]any {
+	return map[string]any{
+		"openai/widgetCSP": map[string]any{
+			"connect_domains":  []string{"https://images.unsplash.com"},
+			"resource_domains": []string{"https://images.unsplash.com"},
+		},
+	}
+}
+
+// widgetResult returns a tool result with text (for Cursor and other
+// clients without widgets), the widget HTML from file embedded as uri, and
+// structuredContent, which ChatGPT passes to the HTML. If the file can't be
+// read, the text and structuredContent are returned alone.
+func widgetResult(ctx context.Context, uri, file, text string, structuredContent any) *mcp.CallToolResult {
+	htmlContent, err := readWidgetHTML(ctx, file)
+	if err != nil {
+		return mcp.NewToolResultStructured(structuredContent, text)
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(text),
+			mcp.NewEmbeddedResource(mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "text/html+skybridge",
+				Text:     string(htmlContent),
+				Meta:     widgetMeta(),
+			}),
+		},
+		StructuredContent: structuredContent,
+	}
+}
+
+// WidgetWatcher keeps the widget resources in step with their files. A
+// widget whose file disappears is removed and one whose file appears is
+// added, which mcp-go announces with notifications/resources/list_changed;
+// subscribers are sent notifications/resources/updated when a file changes.
+type WidgetWatcher struct {
+	cfg           WidgetsConfig
+	server        *server.MCPServer
+	subscriptions *Subscriptions
+
+	mu    sync.Mutex
+	state map[string]string // URI -> file size and modtime, empty if missing
+}
+
+// NewWidgetWatcher registers the widgets whose files exist.
+func NewWidgetWatcher(cfg WidgetsConfig, s *server.MCPServer, subscriptions *Subscriptions) *WidgetWatcher {
+	w := &WidgetWatcher{
+		cfg:           cfg,
+		server:        s,
+		subscriptions: subscriptions,
+		state:         make(map[string]string),
+	}
+	w.Check()
+	return w
+}
+
+// Check compares every widget file with its last known state and
+// registers, removes or notifies accordingly.
+func (w *WidgetWatcher) Check() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, wg := range widgets {
+		uri := wg.resource.URI
+		previous, known := w.state[uri]
+		current := fileState(wg.file)
+		if known && current == previous {
+			continue
+		}
+		w.state[uri] = current
+
+		switch {
+		case current == "" && previous != "":
+			log.Printf("Widget file %s is gone, removing %s", wg.file, uri)
+			w.server.DeleteResources(uri)
+		case current == "":
+			log.Printf("Warning: widget file %s not found, %s is not served", wg.file, uri)
+		case previous == "":
+			w.server.AddResource(wg.resource, widgetHandler(wg))
+			if known {
+				log.Printf("Widget file %s is back, serving %s", wg.file, uri)
+				w.subscriptions.Notify(uri)
+			}
+		default:
+			w.subscriptions.Notify(uri)
+		}
+	}
+}
+
+// Watch checks the widget files every interval until ctx is done.
+func (w *WidgetWatcher) Watch(ctx context.Context) {
+	interval := seconds(w.cfg.WatchInterval)
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// fileState identifies the current contents of path by size and modtime,
+// or returns "" if it can't be read.
+func fileState(path string) string {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return ""
+	}
+	return fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+}